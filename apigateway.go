@@ -0,0 +1,148 @@
+package main
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/apigateway"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// The method settings key holding the defaults applied to every method of a stage
+const apiGatewayAllMethodsSettingsKey = "*/*"
+
+// APIGatewayExporter defines an instance of the API Gateway Exporter
+type APIGatewayExporter struct {
+	sess                   *session.Session
+	ResourcesTotal         *prometheus.Desc
+	StageCachingEnabled    *prometheus.Desc
+	StageInfo              *prometheus.Desc
+	StageThrottleRateLimit *prometheus.Desc
+
+	enabled bool
+	logger  log.Logger
+}
+
+func init() {
+	RegisterCollector(func(sess *session.Session, namespace string, logger log.Logger) Collector {
+		return NewAPIGatewayExporter(sess, namespace, logger, *apiGatewayEnabled)
+	})
+}
+
+// NewAPIGatewayExporter creates a new APIGatewayExporter instance
+func NewAPIGatewayExporter(sess *session.Session, namespace string, logger log.Logger, enabled bool) *APIGatewayExporter {
+	return &APIGatewayExporter{
+		sess:    sess,
+		enabled: enabled,
+		ResourcesTotal: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "apigateway_resources_total"),
+			"The number of API Gateway REST APIs listed by the collector.",
+			[]string{"aws_region"},
+			nil,
+		),
+		StageCachingEnabled: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "apigateway_stage_caching_enabled"),
+			"Indicates if a cache cluster is enabled for the stage.",
+			[]string{"aws_region", "api_id", "api_name", "stage_name"},
+			nil,
+		),
+		StageInfo: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "apigateway_stage_info"),
+			"The deployment of the REST API stage. The value is always 1.",
+			[]string{"aws_region", "api_id", "api_name", "stage_name", "deployment_id"},
+			nil,
+		),
+		StageThrottleRateLimit: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "apigateway_stage_throttle_rate_limit"),
+			"The steady-state request rate limit applied to all the methods of the stage.",
+			[]string{"aws_region", "api_id", "api_name", "stage_name"},
+			nil,
+		),
+		logger: logger,
+	}
+}
+
+// Name returns the name of the collector
+func (e *APIGatewayExporter) Name() string {
+	return "apigateway"
+}
+
+// Enabled returns true if the collector has to be registered
+func (e *APIGatewayExporter) Enabled() bool {
+	return e.enabled
+}
+
+// FiltersTags returns true, the collector applies the tag filter
+func (e *APIGatewayExporter) FiltersTags() bool {
+	return true
+}
+
+// Preflight checks the IAM permissions of the collector with a single call
+func (e *APIGatewayExporter) Preflight() (string, error) {
+	_, err := apigateway.New(e.sess).GetRestApis(&apigateway.GetRestApisInput{Limit: aws.Int64(1)})
+	return "apigateway:GET", err
+}
+
+// Describe is used by the Prometheus client to return a description of the metrics
+func (e *APIGatewayExporter) Describe(ch chan<- *prometheus.Desc) {
+	ch <- e.ResourcesTotal
+	ch <- e.StageCachingEnabled
+	ch <- e.StageInfo
+	ch <- e.StageThrottleRateLimit
+}
+
+// Collect is used by the Prometheus client to collect and return the metrics values
+func (e *APIGatewayExporter) Collect(ch chan<- prometheus.Metric) {
+	region := sessionRegion(e.sess)
+	svc := apigateway.New(e.sess)
+	input := &apigateway.GetRestApisInput{}
+
+	// Get all REST APIs.
+	// If a Position is found, do pagination until last page
+	var apis []*apigateway.RestApi
+	for {
+		exporterMetrics.IncrementRequests(apigateway.ServiceName, "GetRestApis")
+		result, err := svc.GetRestApis(input)
+		if err != nil {
+			level.Error(e.logger).Log("msg", "Call to GetRestApis failed", "region", region, "err", err)
+			exporterMetrics.IncrementErrors(apigateway.ServiceName, "GetRestApis", err)
+			return
+		}
+		for _, api := range result.Items {
+			if !tagFilter.Includes(api.Tags) {
+				continue
+			}
+			apis = append(apis, api)
+		}
+		input.Position = result.Position
+		if result.Position == nil {
+			break
+		}
+	}
+	ch <- prometheus.MustNewConstMetric(e.ResourcesTotal, prometheus.GaugeValue, float64(len(apis)), region)
+
+	for _, api := range apis {
+		exporterMetrics.IncrementRequests(apigateway.ServiceName, "GetStages")
+		result, err := svc.GetStages(&apigateway.GetStagesInput{RestApiId: api.Id})
+		if err != nil {
+			level.Error(e.logger).Log("msg", "Call to GetStages failed", "region", region, "api", *api.Id, "err", err)
+			exporterMetrics.IncrementErrors(apigateway.ServiceName, "GetStages", err)
+			continue
+		}
+
+		apiName := aws.StringValue(api.Name)
+		for _, stage := range result.Item {
+			stageName := aws.StringValue(stage.StageName)
+			ch <- prometheus.MustNewConstMetric(e.StageInfo, prometheus.GaugeValue, 1, region, *api.Id, apiName, stageName, aws.StringValue(stage.DeploymentId))
+			if aws.BoolValue(stage.CacheClusterEnabled) {
+				ch <- prometheus.MustNewConstMetric(e.StageCachingEnabled, prometheus.GaugeValue, 1, region, *api.Id, apiName, stageName)
+			} else {
+				ch <- prometheus.MustNewConstMetric(e.StageCachingEnabled, prometheus.GaugeValue, 0, region, *api.Id, apiName, stageName)
+			}
+			if settings, ok := stage.MethodSettings[apiGatewayAllMethodsSettingsKey]; ok && settings.ThrottlingRateLimit != nil {
+				ch <- prometheus.MustNewConstMetric(e.StageThrottleRateLimit, prometheus.GaugeValue, *settings.ThrottlingRateLimit, region, *api.Id, apiName, stageName)
+			}
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/autoscaling"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// AutoScalingExporter defines an instance of the Auto Scaling Exporter
+type AutoScalingExporter struct {
+	sess                   *session.Session
+	DesiredCapacity        *prometheus.Desc
+	InServiceInstanceCount *prometheus.Desc
+	MaxSize                *prometheus.Desc
+	MinSize                *prometheus.Desc
+	ResourcesTotal         *prometheus.Desc
+
+	enabled bool
+	logger  log.Logger
+}
+
+func init() {
+	RegisterCollector(func(sess *session.Session, namespace string, logger log.Logger) Collector {
+		return NewAutoScalingExporter(sess, namespace, logger, *autoScalingEnabled)
+	})
+}
+
+// NewAutoScalingExporter creates a new AutoScalingExporter instance
+func NewAutoScalingExporter(sess *session.Session, namespace string, logger log.Logger, enabled bool) *AutoScalingExporter {
+	return &AutoScalingExporter{
+		sess:    sess,
+		enabled: enabled,
+		DesiredCapacity: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "asg_desired_capacity"),
+			"The desired number of instances of the Auto Scaling group.",
+			[]string{"aws_region", "autoscaling_group_name"},
+			nil,
+		),
+		InServiceInstanceCount: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "asg_in_service_instance_count"),
+			"The number of InService instances of the Auto Scaling group.",
+			[]string{"aws_region", "autoscaling_group_name"},
+			nil,
+		),
+		MaxSize: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "asg_max_size"),
+			"The maximum number of instances of the Auto Scaling group.",
+			[]string{"aws_region", "autoscaling_group_name"},
+			nil,
+		),
+		MinSize: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "asg_min_size"),
+			"The minimum number of instances of the Auto Scaling group.",
+			[]string{"aws_region", "autoscaling_group_name"},
+			nil,
+		),
+		ResourcesTotal: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "asg_resources_total"),
+			"The number of Auto Scaling groups listed by the collector.",
+			[]string{"aws_region"},
+			nil,
+		),
+		logger: logger,
+	}
+}
+
+// Name returns the name of the collector
+func (e *AutoScalingExporter) Name() string {
+	return "autoscaling"
+}
+
+// Enabled returns true if the collector has to be registered
+func (e *AutoScalingExporter) Enabled() bool {
+	return e.enabled
+}
+
+// FiltersTags returns true, the collector applies the tag filter
+func (e *AutoScalingExporter) FiltersTags() bool {
+	return true
+}
+
+// Preflight checks the IAM permissions of the collector with a single call
+func (e *AutoScalingExporter) Preflight() (string, error) {
+	_, err := autoscaling.New(e.sess).DescribeAutoScalingGroups(&autoscaling.DescribeAutoScalingGroupsInput{MaxRecords: aws.Int64(1)})
+	return "autoscaling:DescribeAutoScalingGroups", err
+}
+
+// Describe is used by the Prometheus client to return a description of the metrics
+func (e *AutoScalingExporter) Describe(ch chan<- *prometheus.Desc) {
+	ch <- e.DesiredCapacity
+	ch <- e.InServiceInstanceCount
+	ch <- e.MaxSize
+	ch <- e.MinSize
+	ch <- e.ResourcesTotal
+}
+
+// Collect is used by the Prometheus client to collect and return the metrics values
+func (e *AutoScalingExporter) Collect(ch chan<- prometheus.Metric) {
+	region := sessionRegion(e.sess)
+	svc := autoscaling.New(e.sess)
+	input := &autoscaling.DescribeAutoScalingGroupsInput{}
+
+	// Get all Auto Scaling groups.
+	// If a NextToken is found, do pagination until last page
+	var groups []*autoscaling.Group
+	for {
+		exporterMetrics.IncrementRequests(autoscaling.ServiceName, "DescribeAutoScalingGroups")
+		result, err := svc.DescribeAutoScalingGroups(input)
+		if err != nil {
+			level.Error(e.logger).Log("msg", "Call to DescribeAutoScalingGroups failed", "region", region, "err", err)
+			exporterMetrics.IncrementErrors(autoscaling.ServiceName, "DescribeAutoScalingGroups", err)
+			return
+		}
+		for _, group := range result.AutoScalingGroups {
+			tags := map[string]*string{}
+			for _, tag := range group.Tags {
+				tags[aws.StringValue(tag.Key)] = tag.Value
+			}
+			if !tagFilter.Includes(tags) {
+				continue
+			}
+			groups = append(groups, group)
+		}
+		input.NextToken = result.NextToken
+		if result.NextToken == nil {
+			break
+		}
+	}
+	ch <- prometheus.MustNewConstMetric(e.ResourcesTotal, prometheus.GaugeValue, float64(len(groups)), region)
+
+	for _, group := range groups {
+		var inService int
+		for _, instance := range group.Instances {
+			if aws.StringValue(instance.LifecycleState) == autoscaling.LifecycleStateInService {
+				inService++
+			}
+		}
+		ch <- prometheus.MustNewConstMetric(e.DesiredCapacity, prometheus.GaugeValue, float64(aws.Int64Value(group.DesiredCapacity)), region, *group.AutoScalingGroupName)
+		ch <- prometheus.MustNewConstMetric(e.MinSize, prometheus.GaugeValue, float64(aws.Int64Value(group.MinSize)), region, *group.AutoScalingGroupName)
+		ch <- prometheus.MustNewConstMetric(e.MaxSize, prometheus.GaugeValue, float64(aws.Int64Value(group.MaxSize)), region, *group.AutoScalingGroupName)
+		ch <- prometheus.MustNewConstMetric(e.InServiceInstanceCount, prometheus.GaugeValue, float64(inService), region, *group.AutoScalingGroupName)
+	}
+}
@@ -0,0 +1,222 @@
+package main
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/batch"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// The job statuses counted for every job queue, finished jobs are kept by AWS Batch for about 24 hours
+var batchJobStatuses = []string{
+	batch.JobStatusSubmitted,
+	batch.JobStatusPending,
+	batch.JobStatusRunnable,
+	batch.JobStatusStarting,
+	batch.JobStatusRunning,
+	batch.JobStatusSucceeded,
+	batch.JobStatusFailed,
+}
+
+// BatchExporter defines an instance of the Batch Exporter
+type BatchExporter struct {
+	sess                     *session.Session
+	ComputeEnvironmentStatus *prometheus.Desc
+	JobQueueInfo             *prometheus.Desc
+	JobsByStatus             *prometheus.Desc
+	ResourcesTotal           *prometheus.Desc
+
+	enabled bool
+	logger  log.Logger
+}
+
+func init() {
+	RegisterCollector(func(sess *session.Session, namespace string, logger log.Logger) Collector {
+		return NewBatchExporter(sess, namespace, logger, *batchEnabled)
+	})
+}
+
+// NewBatchExporter creates a new BatchExporter instance
+func NewBatchExporter(sess *session.Session, namespace string, logger log.Logger, enabled bool) *BatchExporter {
+	return &BatchExporter{
+		sess:    sess,
+		enabled: enabled,
+		ComputeEnvironmentStatus: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "batch_compute_environment_status"),
+			"The state and status of the compute environment. The value is always 1.",
+			[]string{"aws_region", "compute_environment_name", "state", "status"},
+			nil,
+		),
+		JobQueueInfo: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "batch_job_queue_info"),
+			"The state and status of the job queue. The value is always 1.",
+			[]string{"aws_region", "job_queue_name", "state", "status"},
+			nil,
+		),
+		JobsByStatus: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "batch_jobs_by_status"),
+			"The number of jobs of the job queue by status.",
+			[]string{"aws_region", "job_queue_name", "status"},
+			nil,
+		),
+		ResourcesTotal: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "batch_resources_total"),
+			"The number of Batch compute environments listed by the collector.",
+			[]string{"aws_region"},
+			nil,
+		),
+		logger: logger,
+	}
+}
+
+// Name returns the name of the collector
+func (e *BatchExporter) Name() string {
+	return "batch"
+}
+
+// Enabled returns true if the collector has to be registered
+func (e *BatchExporter) Enabled() bool {
+	return e.enabled
+}
+
+// FiltersTags returns true, the collector applies the tag filter
+func (e *BatchExporter) FiltersTags() bool {
+	return true
+}
+
+// Preflight checks the IAM permissions of the collector with a single call
+func (e *BatchExporter) Preflight() (string, error) {
+	_, err := batch.New(e.sess).DescribeComputeEnvironments(&batch.DescribeComputeEnvironmentsInput{MaxResults: aws.Int64(1)})
+	return "batch:DescribeComputeEnvironments", err
+}
+
+// Describe is used by the Prometheus client to return a description of the metrics
+func (e *BatchExporter) Describe(ch chan<- *prometheus.Desc) {
+	ch <- e.ComputeEnvironmentStatus
+	ch <- e.JobQueueInfo
+	ch <- e.JobsByStatus
+	ch <- e.ResourcesTotal
+}
+
+// Collect is used by the Prometheus client to collect and return the metrics values
+func (e *BatchExporter) Collect(ch chan<- prometheus.Metric) {
+	region := sessionRegion(e.sess)
+	svc := batch.New(e.sess)
+
+	// The Resource Groups Tagging API filters the compute environments and job queues on their tags
+	var tagged map[string]bool
+	if tagFilter != nil {
+		arns, err := tagFilter.ResourceARNs(e.sess, "batch")
+		if err != nil {
+			level.Error(e.logger).Log("msg", "Call to GetResources failed", "region", region, "err", err)
+			return
+		}
+		tagged = arns
+	}
+
+	e.collectComputeEnvironments(ch, region, svc, tagged)
+	e.collectJobQueues(ch, region, svc, tagged)
+}
+
+// collectComputeEnvironments collects the status of all the compute environments
+func (e *BatchExporter) collectComputeEnvironments(ch chan<- prometheus.Metric, region string, svc *batch.Batch, tagged map[string]bool) {
+	input := &batch.DescribeComputeEnvironmentsInput{}
+
+	// Get all compute environments.
+	// If a NextToken is found, do pagination until last page
+	var environments []*batch.ComputeEnvironmentDetail
+	for {
+		exporterMetrics.IncrementRequests(batch.ServiceName, "DescribeComputeEnvironments")
+		result, err := svc.DescribeComputeEnvironments(input)
+		if err != nil {
+			level.Error(e.logger).Log("msg", "Call to DescribeComputeEnvironments failed", "region", region, "err", err)
+			exporterMetrics.IncrementErrors(batch.ServiceName, "DescribeComputeEnvironments", err)
+			return
+		}
+		for _, environment := range result.ComputeEnvironments {
+			if tagged != nil && !tagged[aws.StringValue(environment.ComputeEnvironmentArn)] {
+				continue
+			}
+			environments = append(environments, environment)
+		}
+		input.NextToken = result.NextToken
+		if result.NextToken == nil {
+			break
+		}
+	}
+	ch <- prometheus.MustNewConstMetric(e.ResourcesTotal, prometheus.GaugeValue, float64(len(environments)), region)
+
+	for _, environment := range environments {
+		ch <- prometheus.MustNewConstMetric(e.ComputeEnvironmentStatus, prometheus.GaugeValue, 1, region, aws.StringValue(environment.ComputeEnvironmentName), aws.StringValue(environment.State), aws.StringValue(environment.Status))
+	}
+}
+
+// collectJobQueues collects the status and the number of jobs by status of all the job queues
+func (e *BatchExporter) collectJobQueues(ch chan<- prometheus.Metric, region string, svc *batch.Batch, tagged map[string]bool) {
+	input := &batch.DescribeJobQueuesInput{}
+
+	// Get all job queues.
+	// If a NextToken is found, do pagination until last page
+	var queues []*batch.JobQueueDetail
+	for {
+		exporterMetrics.IncrementRequests(batch.ServiceName, "DescribeJobQueues")
+		result, err := svc.DescribeJobQueues(input)
+		if err != nil {
+			level.Error(e.logger).Log("msg", "Call to DescribeJobQueues failed", "region", region, "err", err)
+			exporterMetrics.IncrementErrors(batch.ServiceName, "DescribeJobQueues", err)
+			return
+		}
+		for _, queue := range result.JobQueues {
+			if tagged != nil && !tagged[aws.StringValue(queue.JobQueueArn)] {
+				continue
+			}
+			queues = append(queues, queue)
+		}
+		input.NextToken = result.NextToken
+		if result.NextToken == nil {
+			break
+		}
+	}
+
+	for _, queue := range queues {
+		queueName := aws.StringValue(queue.JobQueueName)
+		ch <- prometheus.MustNewConstMetric(e.JobQueueInfo, prometheus.GaugeValue, 1, region, queueName, aws.StringValue(queue.State), aws.StringValue(queue.Status))
+
+		for _, status := range batchJobStatuses {
+			count, err := e.countJobs(svc, queue.JobQueueArn, status)
+			if err != nil {
+				level.Error(e.logger).Log("msg", "Call to ListJobs failed", "region", region, "queue", queueName, "status", status, "err", err)
+				continue
+			}
+			ch <- prometheus.MustNewConstMetric(e.JobsByStatus, prometheus.GaugeValue, float64(count), region, queueName, status)
+		}
+	}
+}
+
+// countJobs returns the number of jobs of the job queue in the given status
+func (e *BatchExporter) countJobs(svc *batch.Batch, queueArn *string, status string) (int, error) {
+	input := &batch.ListJobsInput{
+		JobQueue:  queueArn,
+		JobStatus: aws.String(status),
+	}
+
+	// Get all jobs.
+	// If a NextToken is found, do pagination until last page
+	var count int
+	for {
+		exporterMetrics.IncrementRequests(batch.ServiceName, "ListJobs")
+		result, err := svc.ListJobs(input)
+		if err != nil {
+			exporterMetrics.IncrementErrors(batch.ServiceName, "ListJobs", err)
+			return 0, err
+		}
+		count += len(result.JobSummaryList)
+		input.NextToken = result.NextToken
+		if result.NextToken == nil {
+			break
+		}
+	}
+	return count, nil
+}
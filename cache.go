@@ -0,0 +1,60 @@
+package main
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// cachingCollector serves the metrics of the wrapped collector from a cache which is
+// refreshed at most once per interval, decoupling the AWS calls from the Prometheus scrapes
+type cachingCollector struct {
+	Collector
+
+	interval    time.Duration
+	metrics     []prometheus.Metric
+	lastRefresh time.Time
+	mutex       *sync.Mutex
+}
+
+func newCachingCollector(collector Collector, interval time.Duration) *cachingCollector {
+	return &cachingCollector{
+		Collector: collector,
+		interval:  interval,
+		mutex:     &sync.Mutex{},
+	}
+}
+
+// Collect is used by the Prometheus client to collect and return the metrics values
+func (c *cachingCollector) Collect(ch chan<- prometheus.Metric) {
+	// Concurrent scrapes wait for the running refresh instead of starting their own
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.lastRefresh.IsZero() || time.Since(c.lastRefresh) >= c.interval {
+		c.refresh()
+	}
+	for _, metric := range c.metrics {
+		ch <- metric
+	}
+}
+
+// refresh replaces the cached metrics with freshly collected ones
+func (c *cachingCollector) refresh() {
+	metricsCh := make(chan prometheus.Metric)
+	done := make(chan struct{})
+	var metrics []prometheus.Metric
+	go func() {
+		for metric := range metricsCh {
+			metrics = append(metrics, metric)
+		}
+		close(done)
+	}()
+	c.Collector.Collect(metricsCh)
+	close(metricsCh)
+	<-done
+
+	c.metrics = metrics
+	c.lastRefresh = time.Now()
+}
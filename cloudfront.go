@@ -0,0 +1,156 @@
+package main
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/cloudfront"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// CloudFront is a global service, its resources are reported in this region label
+const cloudFrontRegion = "global"
+
+// The global resources of CloudFront are managed from us-east-1
+const cloudFrontAPIRegion = "us-east-1"
+
+// CloudFrontExporter defines an instance of the CloudFront Exporter
+type CloudFrontExporter struct {
+	sess                    *session.Session
+	DistributionEnabled     *prometheus.Desc
+	DistributionInfo        *prometheus.Desc
+	DistributionOriginCount *prometheus.Desc
+	ResourcesTotal          *prometheus.Desc
+
+	enabled bool
+	logger  log.Logger
+}
+
+func init() {
+	RegisterCollector(func(sess *session.Session, namespace string, logger log.Logger) Collector {
+		return NewCloudFrontExporter(sess, namespace, logger, *cloudFrontEnabled)
+	})
+}
+
+// NewCloudFrontExporter creates a new CloudFrontExporter instance
+func NewCloudFrontExporter(sess *session.Session, namespace string, logger log.Logger, enabled bool) *CloudFrontExporter {
+	return &CloudFrontExporter{
+		sess:    sess,
+		enabled: enabled,
+		DistributionEnabled: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "cloudfront_distribution_enabled"),
+			"Indicates if the distribution accepts end user requests.",
+			[]string{"aws_region", "distribution_id", "domain_name"},
+			nil,
+		),
+		DistributionInfo: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "cloudfront_distribution_info"),
+			"The status and price class of the distribution. The value is always 1.",
+			[]string{"aws_region", "distribution_id", "domain_name", "status", "price_class"},
+			nil,
+		),
+		DistributionOriginCount: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "cloudfront_distribution_origin_count"),
+			"The number of origins of the distribution.",
+			[]string{"aws_region", "distribution_id", "domain_name"},
+			nil,
+		),
+		ResourcesTotal: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "cloudfront_resources_total"),
+			"The number of CloudFront distributions listed by the collector.",
+			[]string{"aws_region"},
+			nil,
+		),
+		logger: logger,
+	}
+}
+
+// Name returns the name of the collector
+func (e *CloudFrontExporter) Name() string {
+	return "cloudfront"
+}
+
+// Enabled returns true if the collector has to be registered
+func (e *CloudFrontExporter) Enabled() bool {
+	return e.enabled
+}
+
+// FiltersTags returns true, the collector applies the tag filter
+func (e *CloudFrontExporter) FiltersTags() bool {
+	return true
+}
+
+// Global returns true, the collector is only created for the first region
+func (e *CloudFrontExporter) Global() bool {
+	return true
+}
+
+// Preflight checks the IAM permissions of the collector with a single call
+func (e *CloudFrontExporter) Preflight() (string, error) {
+	_, err := cloudfront.New(e.sess).ListDistributions(&cloudfront.ListDistributionsInput{MaxItems: aws.Int64(1)})
+	return "cloudfront:ListDistributions", err
+}
+
+// Describe is used by the Prometheus client to return a description of the metrics
+func (e *CloudFrontExporter) Describe(ch chan<- *prometheus.Desc) {
+	ch <- e.DistributionEnabled
+	ch <- e.DistributionInfo
+	ch <- e.DistributionOriginCount
+	ch <- e.ResourcesTotal
+}
+
+// Collect is used by the Prometheus client to collect and return the metrics values
+func (e *CloudFrontExporter) Collect(ch chan<- prometheus.Metric) {
+	svc := cloudfront.New(e.sess)
+
+	// The Resource Groups Tagging API filters the distributions on their tags, their tags are only available from us-east-1
+	var taggedDistributions map[string]bool
+	if tagFilter != nil {
+		arns, err := tagFilter.ResourceARNs(e.sess.Copy(aws.NewConfig().WithRegion(cloudFrontAPIRegion)), "cloudfront:distribution")
+		if err != nil {
+			level.Error(e.logger).Log("msg", "Call to GetResources failed", "region", cloudFrontRegion, "err", err)
+			return
+		}
+		taggedDistributions = arns
+	}
+
+	input := &cloudfront.ListDistributionsInput{}
+
+	// Get all distributions.
+	// If the list is truncated, do pagination from the NextMarker until last page
+	var distributions []*cloudfront.DistributionSummary
+	for {
+		exporterMetrics.IncrementRequests(cloudfront.ServiceName, "ListDistributions")
+		result, err := svc.ListDistributions(input)
+		if err != nil {
+			level.Error(e.logger).Log("msg", "Call to ListDistributions failed", "region", cloudFrontRegion, "err", err)
+			exporterMetrics.IncrementErrors(cloudfront.ServiceName, "ListDistributions", err)
+			return
+		}
+		for _, distribution := range result.DistributionList.Items {
+			if taggedDistributions != nil && !taggedDistributions[aws.StringValue(distribution.ARN)] {
+				continue
+			}
+			distributions = append(distributions, distribution)
+		}
+		if !aws.BoolValue(result.DistributionList.IsTruncated) {
+			break
+		}
+		input.Marker = result.DistributionList.NextMarker
+	}
+	ch <- prometheus.MustNewConstMetric(e.ResourcesTotal, prometheus.GaugeValue, float64(len(distributions)), cloudFrontRegion)
+
+	for _, distribution := range distributions {
+		domainName := aws.StringValue(distribution.DomainName)
+		if aws.BoolValue(distribution.Enabled) {
+			ch <- prometheus.MustNewConstMetric(e.DistributionEnabled, prometheus.GaugeValue, 1, cloudFrontRegion, *distribution.Id, domainName)
+		} else {
+			ch <- prometheus.MustNewConstMetric(e.DistributionEnabled, prometheus.GaugeValue, 0, cloudFrontRegion, *distribution.Id, domainName)
+		}
+		ch <- prometheus.MustNewConstMetric(e.DistributionInfo, prometheus.GaugeValue, 1, cloudFrontRegion, *distribution.Id, domainName, aws.StringValue(distribution.Status), aws.StringValue(distribution.PriceClass))
+		if distribution.Origins != nil {
+			ch <- prometheus.MustNewConstMetric(e.DistributionOriginCount, prometheus.GaugeValue, float64(aws.Int64Value(distribution.Origins.Quantity)), cloudFrontRegion, *distribution.Id, domainName)
+		}
+	}
+}
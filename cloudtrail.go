@@ -0,0 +1,125 @@
+package main
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/cloudtrail"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// CloudTrailExporter defines an instance of the CloudTrail Exporter
+type CloudTrailExporter struct {
+	sess           *session.Session
+	IsMultiRegion  *prometheus.Desc
+	LoggingEnabled *prometheus.Desc
+	ResourcesTotal *prometheus.Desc
+
+	enabled bool
+	logger  log.Logger
+}
+
+func init() {
+	RegisterCollector(func(sess *session.Session, namespace string, logger log.Logger) Collector {
+		return NewCloudTrailExporter(sess, namespace, logger, *cloudTrailEnabled)
+	})
+}
+
+// NewCloudTrailExporter creates a new CloudTrailExporter instance
+func NewCloudTrailExporter(sess *session.Session, namespace string, logger log.Logger, enabled bool) *CloudTrailExporter {
+	return &CloudTrailExporter{
+		sess:    sess,
+		enabled: enabled,
+		IsMultiRegion: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "cloudtrail_is_multi_region"),
+			"Indicates if the trail logs the events of all the regions.",
+			[]string{"aws_region", "trail_name"},
+			nil,
+		),
+		LoggingEnabled: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "cloudtrail_logging_enabled"),
+			"Indicates if the trail is logging events.",
+			[]string{"aws_region", "trail_name"},
+			nil,
+		),
+		ResourcesTotal: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "cloudtrail_resources_total"),
+			"The number of CloudTrail trails listed by the collector.",
+			[]string{"aws_region"},
+			nil,
+		),
+		logger: logger,
+	}
+}
+
+// Name returns the name of the collector
+func (e *CloudTrailExporter) Name() string {
+	return "cloudtrail"
+}
+
+// Enabled returns true if the collector has to be registered
+func (e *CloudTrailExporter) Enabled() bool {
+	return e.enabled
+}
+
+// Preflight checks the IAM permissions of the collector with a single call
+func (e *CloudTrailExporter) Preflight() (string, error) {
+	_, err := cloudtrail.New(e.sess).DescribeTrails(&cloudtrail.DescribeTrailsInput{IncludeShadowTrails: aws.Bool(false)})
+	return "cloudtrail:DescribeTrails", err
+}
+
+// Describe is used by the Prometheus client to return a description of the metrics
+func (e *CloudTrailExporter) Describe(ch chan<- *prometheus.Desc) {
+	ch <- e.IsMultiRegion
+	ch <- e.LoggingEnabled
+	ch <- e.ResourcesTotal
+}
+
+// Collect is used by the Prometheus client to collect and return the metrics values
+func (e *CloudTrailExporter) Collect(ch chan<- prometheus.Metric) {
+	region := sessionRegion(e.sess)
+	svc := cloudtrail.New(e.sess)
+
+	// Multi-region trails are replicated as shadow trails in the other regions,
+	// they are only reported by the exporters of their home region
+	exporterMetrics.IncrementRequests(cloudtrail.ServiceName, "DescribeTrails")
+	result, err := svc.DescribeTrails(&cloudtrail.DescribeTrailsInput{
+		IncludeShadowTrails: aws.Bool(false),
+	})
+	if err != nil {
+		level.Error(e.logger).Log("msg", "Call to DescribeTrails failed", "region", region, "err", err)
+		exporterMetrics.IncrementErrors(cloudtrail.ServiceName, "DescribeTrails", err)
+		return
+	}
+	ch <- prometheus.MustNewConstMetric(e.ResourcesTotal, prometheus.GaugeValue, float64(len(result.TrailList)), region)
+
+	for _, trail := range result.TrailList {
+		trailName := aws.StringValue(trail.Name)
+		if aws.BoolValue(trail.IsMultiRegionTrail) {
+			ch <- prometheus.MustNewConstMetric(e.IsMultiRegion, prometheus.GaugeValue, 1, region, trailName)
+		} else {
+			ch <- prometheus.MustNewConstMetric(e.IsMultiRegion, prometheus.GaugeValue, 0, region, trailName)
+		}
+
+		exporterMetrics.IncrementRequests(cloudtrail.ServiceName, "GetTrailStatus")
+		status, err := svc.GetTrailStatus(&cloudtrail.GetTrailStatusInput{
+			Name: trail.TrailARN,
+		})
+		if err != nil {
+			exporterMetrics.IncrementErrors(cloudtrail.ServiceName, "GetTrailStatus", err)
+			// The status of an organization trail can only be read from the management account
+			if aws.BoolValue(trail.IsOrganizationTrail) {
+				level.Debug(e.logger).Log("msg", "Could not get the status of the organization trail", "region", region, "trail", trailName, "err", err)
+			} else {
+				level.Error(e.logger).Log("msg", "Call to GetTrailStatus failed", "region", region, "trail", trailName, "err", err)
+			}
+			continue
+		}
+		if aws.BoolValue(status.IsLogging) {
+			ch <- prometheus.MustNewConstMetric(e.LoggingEnabled, prometheus.GaugeValue, 1, region, trailName)
+		} else {
+			ch <- prometheus.MustNewConstMetric(e.LoggingEnabled, prometheus.GaugeValue, 0, region, trailName)
+		}
+	}
+}
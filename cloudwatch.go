@@ -0,0 +1,333 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/cloudwatch"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/model"
+)
+
+// metricDataQueriesLimit is the maximum number of queries of a single CloudWatch GetMetricData call
+const metricDataQueriesLimit = 500
+
+// CloudWatchMetric is a CloudWatch metric exported by the CloudWatch collector
+type CloudWatchMetric struct {
+	Namespace  string
+	MetricName string
+	Dimensions map[string]string
+	Statistic  string
+	Period     time.Duration
+}
+
+// ParseCloudWatchMetric parses a metric definition such as
+// namespace=AWS/SQS,metric=ApproximateAgeOfOldestMessage,statistic=Maximum,period=5m,dimension.QueueName=jobs
+func ParseCloudWatchMetric(definition string) (CloudWatchMetric, error) {
+	metric := CloudWatchMetric{
+		Dimensions: map[string]string{},
+		Statistic:  cloudwatch.StatisticAverage,
+		Period:     5 * time.Minute,
+	}
+	for _, field := range strings.Split(definition, ",") {
+		parts := strings.SplitN(field, "=", 2)
+		if len(parts) != 2 {
+			return metric, fmt.Errorf("invalid field %q in CloudWatch metric %q, expected key=value", field, definition)
+		}
+		key, value := parts[0], parts[1]
+		switch {
+		case key == "namespace":
+			metric.Namespace = value
+		case key == "metric":
+			metric.MetricName = value
+		case key == "statistic":
+			metric.Statistic = value
+		case key == "period":
+			period, err := time.ParseDuration(value)
+			if err != nil {
+				return metric, fmt.Errorf("invalid period in CloudWatch metric %q: %s", definition, err)
+			}
+			metric.Period = period
+		case strings.HasPrefix(key, "dimension."):
+			metric.Dimensions[strings.TrimPrefix(key, "dimension.")] = value
+		default:
+			return metric, fmt.Errorf("unknown field %q in CloudWatch metric %q", key, definition)
+		}
+	}
+	if metric.Namespace == "" || metric.MetricName == "" {
+		return metric, fmt.Errorf("CloudWatch metric %q requires a namespace and a metric", definition)
+	}
+	if metric.Period < time.Minute || metric.Period%time.Minute != 0 {
+		return metric, fmt.Errorf("period of CloudWatch metric %q has to be a multiple of 1m", definition)
+	}
+	return metric, nil
+}
+
+// ParseCloudWatchMetrics parses all the metric definitions and checks that they can be exported together:
+// the metrics sharing a name are exported with the union of their dimensions as labels
+func ParseCloudWatchMetrics(definitions []string) ([]CloudWatchMetric, error) {
+	metrics := make([]CloudWatchMetric, 0, len(definitions))
+	for _, definition := range definitions {
+		metric, err := ParseCloudWatchMetric(definition)
+		if err != nil {
+			return nil, err
+		}
+		metrics = append(metrics, metric)
+	}
+
+	// Different CloudWatch names can't end up in the same metric, nor different dimensions in the same label
+	metricNames := map[string]string{}
+	dimensionNames := map[string]map[string]string{}
+	for _, metric := range metrics {
+		name := metric.exportedName()
+		if other, ok := metricNames[name]; ok && other != metric.MetricName {
+			return nil, fmt.Errorf("CloudWatch metrics %s and %s are both exported as %s", other, metric.MetricName, name)
+		}
+		metricNames[name] = metric.MetricName
+		if dimensionNames[name] == nil {
+			dimensionNames[name] = map[string]string{}
+		}
+		for dimension := range metric.Dimensions {
+			label := toSnakeCase(dimension)
+			if !model.LabelName(label).IsValid() || label == "aws_region" || label == "namespace" || label == "statistic" {
+				return nil, fmt.Errorf("dimension %s of CloudWatch metric %s can't be exported as the %s label", dimension, metric.MetricName, label)
+			}
+			if other, ok := dimensionNames[name][label]; ok && other != dimension {
+				return nil, fmt.Errorf("dimensions %s and %s of CloudWatch metric %s are both exported as the %s label", other, dimension, metric.MetricName, label)
+			}
+			dimensionNames[name][label] = dimension
+		}
+	}
+
+	// Every metric has to be a distinct series
+	dimensions := metricDimensions(metrics)
+	series := map[string]bool{}
+	for _, metric := range metrics {
+		key := strings.Join(append([]string{metric.exportedName()}, metric.labelValues("", dimensions[metric.exportedName()])...), "\xff")
+		if series[key] {
+			return nil, fmt.Errorf("CloudWatch metric %s with statistic %s and dimensions %v is configured twice", metric.MetricName, metric.Statistic, metric.Dimensions)
+		}
+		series[key] = true
+	}
+	return metrics, nil
+}
+
+// exportedName returns the name of the gauge the metric is exported as, without the namespace
+func (m CloudWatchMetric) exportedName() string {
+	return "cloudwatch_" + toSnakeCase(m.MetricName)
+}
+
+// dimensionNames returns the sorted names of the dimensions of the metric
+func (m CloudWatchMetric) dimensionNames() []string {
+	names := make([]string, 0, len(m.Dimensions))
+	for name := range m.Dimensions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// labelValues returns the values of the labels of the metric in the given region,
+// the dimensions the metric doesn't have are left empty
+func (m CloudWatchMetric) labelValues(region string, dimensions []string) []string {
+	values := []string{region, m.Namespace, m.Statistic}
+	for _, name := range dimensions {
+		values = append(values, m.Dimensions[name])
+	}
+	return values
+}
+
+// metricDimensions returns the sorted union of the dimension names of the metrics, by exported name
+func metricDimensions(metrics []CloudWatchMetric) map[string][]string {
+	union := map[string]map[string]bool{}
+	for _, metric := range metrics {
+		name := metric.exportedName()
+		if union[name] == nil {
+			union[name] = map[string]bool{}
+		}
+		for dimension := range metric.Dimensions {
+			union[name][dimension] = true
+		}
+	}
+	dimensions := map[string][]string{}
+	for name, set := range union {
+		dimensions[name] = []string{}
+		for dimension := range set {
+			dimensions[name] = append(dimensions[name], dimension)
+		}
+		sort.Strings(dimensions[name])
+	}
+	return dimensions
+}
+
+// CloudWatchExporter defines an instance of the CloudWatch Exporter
+type CloudWatchExporter struct {
+	sess       *session.Session
+	metrics    []CloudWatchMetric
+	descs      map[string]*prometheus.Desc
+	dimensions map[string][]string
+
+	logger log.Logger
+}
+
+func init() {
+	RegisterCollector(func(sess *session.Session, namespace string, logger log.Logger) Collector {
+		return NewCloudWatchExporter(sess, namespace, logger, cloudWatchMetrics)
+	})
+}
+
+// NewCloudWatchExporter creates a new CloudWatchExporter instance exporting the given metrics,
+// which have been checked by ParseCloudWatchMetrics
+func NewCloudWatchExporter(sess *session.Session, namespace string, logger log.Logger, metrics []CloudWatchMetric) *CloudWatchExporter {
+	e := &CloudWatchExporter{
+		sess:       sess,
+		metrics:    metrics,
+		descs:      map[string]*prometheus.Desc{},
+		dimensions: metricDimensions(metrics),
+		logger:     logger,
+	}
+	for _, metric := range metrics {
+		name := metric.exportedName()
+		if _, ok := e.descs[name]; ok {
+			continue
+		}
+		labels := []string{"aws_region", "namespace", "statistic"}
+		for _, dimension := range e.dimensions[name] {
+			labels = append(labels, toSnakeCase(dimension))
+		}
+		e.descs[name] = prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", name),
+			fmt.Sprintf("The latest datapoint of the %s CloudWatch metric.", metric.MetricName),
+			labels,
+			nil,
+		)
+	}
+	return e
+}
+
+// Name returns the name of the collector
+func (e *CloudWatchExporter) Name() string {
+	return "cloudwatch"
+}
+
+// Enabled returns true if the collector has to be registered
+// The collector is enabled as soon as a metric is configured
+func (e *CloudWatchExporter) Enabled() bool {
+	return len(e.metrics) > 0
+}
+
+// Describe is used by the Prometheus client to return a description of the metrics
+func (e *CloudWatchExporter) Describe(ch chan<- *prometheus.Desc) {
+	for _, desc := range e.descs {
+		ch <- desc
+	}
+}
+
+// Collect is used by the Prometheus client to collect and return the metrics values
+func (e *CloudWatchExporter) Collect(ch chan<- prometheus.Metric) {
+	region := sessionRegion(e.sess)
+	svc := cloudwatch.New(e.sess)
+	now := time.Now()
+
+	// GetMetricData accepts at most metricDataQueriesLimit queries per call
+	for start := 0; start < len(e.metrics); start += metricDataQueriesLimit {
+		end := start + metricDataQueriesLimit
+		if end > len(e.metrics) {
+			end = len(e.metrics)
+		}
+
+		var longestPeriod time.Duration
+		queries := make([]*cloudwatch.MetricDataQuery, 0, end-start)
+		for i, metric := range e.metrics[start:end] {
+			dimensions := make([]*cloudwatch.Dimension, 0, len(metric.Dimensions))
+			for _, name := range metric.dimensionNames() {
+				dimensions = append(dimensions, &cloudwatch.Dimension{
+					Name:  aws.String(name),
+					Value: aws.String(metric.Dimensions[name]),
+				})
+			}
+			queries = append(queries, &cloudwatch.MetricDataQuery{
+				Id: aws.String(fmt.Sprintf("m%d", start+i)),
+				MetricStat: &cloudwatch.MetricStat{
+					Metric: &cloudwatch.Metric{
+						Namespace:  aws.String(metric.Namespace),
+						MetricName: aws.String(metric.MetricName),
+						Dimensions: dimensions,
+					},
+					Period: aws.Int64(int64(metric.Period.Seconds())),
+					Stat:   aws.String(metric.Statistic),
+				},
+			})
+			if metric.Period > longestPeriod {
+				longestPeriod = metric.Period
+			}
+		}
+		input := &cloudwatch.GetMetricDataInput{
+			MetricDataQueries: queries,
+			// Look back over a few periods as the latest datapoint can be published late
+			StartTime: aws.Time(now.Add(-3 * longestPeriod)),
+			EndTime:   aws.Time(now),
+			ScanBy:    aws.String(cloudwatch.ScanByTimestampDescending),
+		}
+
+		// Get the latest datapoint of every query.
+		// If a NextToken is found, do pagination until last page
+		latest := map[string]float64{}
+		for {
+			exporterMetrics.IncrementRequests(cloudwatch.ServiceName, "GetMetricData")
+			result, err := svc.GetMetricData(input)
+			if err != nil {
+				level.Error(e.logger).Log("msg", "Call to GetMetricData failed", "region", region, "err", err)
+				exporterMetrics.IncrementErrors(cloudwatch.ServiceName, "GetMetricData", err)
+				return
+			}
+			for _, data := range result.MetricDataResults {
+				if _, ok := latest[*data.Id]; !ok && len(data.Values) > 0 {
+					latest[*data.Id] = *data.Values[0]
+				}
+			}
+			input.NextToken = result.NextToken
+			if result.NextToken == nil {
+				break
+			}
+		}
+
+		for i, metric := range e.metrics[start:end] {
+			value, ok := latest[fmt.Sprintf("m%d", start+i)]
+			if !ok {
+				continue
+			}
+			name := metric.exportedName()
+			ch <- prometheus.MustNewConstMetric(e.descs[name], prometheus.GaugeValue, value, metric.labelValues(region, e.dimensions[name])...)
+		}
+	}
+}
+
+// toSnakeCase converts a CloudWatch metric or dimension name such as DBInstanceIdentifier
+// to a Prometheus name such as db_instance_identifier
+func toSnakeCase(name string) string {
+	runes := []rune(name)
+	var b strings.Builder
+	for i, r := range runes {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			b.WriteRune('_')
+			continue
+		}
+		if unicode.IsUpper(r) && i > 0 {
+			prev := runes[i-1]
+			nextIsLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || (unicode.IsUpper(prev) && nextIsLower) {
+				b.WriteRune('_')
+			}
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
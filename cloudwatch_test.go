@@ -0,0 +1,82 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/go-kit/kit/log"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestParseCloudWatchMetrics(t *testing.T) {
+	tests := []struct {
+		name        string
+		definitions []string
+		wantErr     bool
+	}{
+		{
+			name: "same metric in different namespaces",
+			definitions: []string{
+				"namespace=AWS/EC2,metric=CPUUtilization,dimension.InstanceId=i-1",
+				"namespace=AWS/RDS,metric=CPUUtilization,dimension.DBInstanceIdentifier=db1",
+			},
+		},
+		{
+			name: "same metric with different dimensions",
+			definitions: []string{
+				"namespace=AWS/EC2,metric=CPUUtilization,dimension.InstanceId=i-1",
+				"namespace=AWS/EC2,metric=CPUUtilization,dimension.AutoScalingGroupName=asg",
+			},
+		},
+		{
+			name: "same metric with different statistics",
+			definitions: []string{
+				"namespace=AWS/EC2,metric=CPUUtilization,statistic=Average,dimension.InstanceId=i-1",
+				"namespace=AWS/EC2,metric=CPUUtilization,statistic=Maximum,dimension.InstanceId=i-1",
+			},
+		},
+		{
+			name: "same series twice",
+			definitions: []string{
+				"namespace=AWS/EC2,metric=CPUUtilization,dimension.InstanceId=i-1",
+				"namespace=AWS/EC2,metric=CPUUtilization,period=10m,dimension.InstanceId=i-1",
+			},
+			wantErr: true,
+		},
+		{
+			name: "metrics exported under the same name",
+			definitions: []string{
+				"namespace=AWS/EC2,metric=CPUUtilization",
+				"namespace=Custom,metric=CpuUtilization",
+			},
+			wantErr: true,
+		},
+		{
+			name: "dimensions exported as the same label",
+			definitions: []string{
+				"namespace=AWS/EC2,metric=CPUUtilization,dimension.InstanceId=i-1",
+				"namespace=Custom,metric=CPUUtilization,dimension.instance_id=i-1",
+			},
+			wantErr: true,
+		},
+		{
+			name:        "dimension clashing with a fixed label",
+			definitions: []string{"namespace=Custom,metric=Jobs,dimension.Namespace=default"},
+			wantErr:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			metrics, err := ParseCloudWatchMetrics(tt.definitions)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseCloudWatchMetrics() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			registry := prometheus.NewRegistry()
+			if err := registry.Register(NewCloudWatchExporter(nil, defaultNamespace, log.NewNopLogger(), metrics)); err != nil {
+				t.Errorf("Register() error = %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/cloudwatch"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// The states of a CloudWatch alarm, every state is exported with 1 for the current one
+var cloudWatchAlarmStates = []string{
+	cloudwatch.StateValueOk,
+	cloudwatch.StateValueAlarm,
+	cloudwatch.StateValueInsufficientData,
+}
+
+// CloudWatchAlarmsExporter defines an instance of the CloudWatch Alarms Exporter
+type CloudWatchAlarmsExporter struct {
+	sess           *session.Session
+	AlarmState     *prometheus.Desc
+	AlarmsInAlarm  *prometheus.Desc
+	ResourcesTotal *prometheus.Desc
+
+	enabled bool
+	logger  log.Logger
+}
+
+func init() {
+	RegisterCollector(func(sess *session.Session, namespace string, logger log.Logger) Collector {
+		return NewCloudWatchAlarmsExporter(sess, namespace, logger, *cloudWatchAlarmsEnabled)
+	})
+}
+
+// NewCloudWatchAlarmsExporter creates a new CloudWatchAlarmsExporter instance
+func NewCloudWatchAlarmsExporter(sess *session.Session, namespace string, logger log.Logger, enabled bool) *CloudWatchAlarmsExporter {
+	return &CloudWatchAlarmsExporter{
+		sess:    sess,
+		enabled: enabled,
+		AlarmState: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "cloudwatch_alarm_state"),
+			"Indicates the current state of the alarm.",
+			[]string{"aws_region", "alarm_name", "namespace", "state"},
+			nil,
+		),
+		AlarmsInAlarm: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "cloudwatch_alarms_in_alarm_total"),
+			"The number of alarms in the ALARM state.",
+			[]string{"aws_region"},
+			nil,
+		),
+		ResourcesTotal: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "cloudwatch_alarm_resources_total"),
+			"The number of CloudWatch alarms listed by the collector.",
+			[]string{"aws_region"},
+			nil,
+		),
+		logger: logger,
+	}
+}
+
+// Name returns the name of the collector
+func (e *CloudWatchAlarmsExporter) Name() string {
+	return "cloudwatchalarms"
+}
+
+// Enabled returns true if the collector has to be registered
+func (e *CloudWatchAlarmsExporter) Enabled() bool {
+	return e.enabled
+}
+
+// FiltersTags returns true, the collector applies the tag filter
+func (e *CloudWatchAlarmsExporter) FiltersTags() bool {
+	return true
+}
+
+// Preflight checks the IAM permissions of the collector with a single call
+func (e *CloudWatchAlarmsExporter) Preflight() (string, error) {
+	_, err := cloudwatch.New(e.sess).DescribeAlarms(&cloudwatch.DescribeAlarmsInput{MaxRecords: aws.Int64(1)})
+	return "cloudwatch:DescribeAlarms", err
+}
+
+// Describe is used by the Prometheus client to return a description of the metrics
+func (e *CloudWatchAlarmsExporter) Describe(ch chan<- *prometheus.Desc) {
+	ch <- e.AlarmState
+	ch <- e.AlarmsInAlarm
+	ch <- e.ResourcesTotal
+}
+
+// Collect is used by the Prometheus client to collect and return the metrics values
+func (e *CloudWatchAlarmsExporter) Collect(ch chan<- prometheus.Metric) {
+	region := sessionRegion(e.sess)
+	svc := cloudwatch.New(e.sess)
+
+	// The Resource Groups Tagging API filters the alarms on their tags
+	var taggedAlarms map[string]bool
+	if tagFilter != nil {
+		arns, err := tagFilter.ResourceARNs(e.sess, "cloudwatch:alarm")
+		if err != nil {
+			level.Error(e.logger).Log("msg", "Call to GetResources failed", "region", region, "err", err)
+			return
+		}
+		taggedAlarms = arns
+	}
+
+	input := &cloudwatch.DescribeAlarmsInput{}
+
+	// Get all alarms.
+	// If a NextToken is found, do pagination until last page
+	var alarms []*cloudwatch.MetricAlarm
+	for {
+		exporterMetrics.IncrementRequests(cloudwatch.ServiceName, "DescribeAlarms")
+		result, err := svc.DescribeAlarms(input)
+		if err != nil {
+			level.Error(e.logger).Log("msg", "Call to DescribeAlarms failed", "region", region, "err", err)
+			exporterMetrics.IncrementErrors(cloudwatch.ServiceName, "DescribeAlarms", err)
+			return
+		}
+		for _, alarm := range result.MetricAlarms {
+			if taggedAlarms != nil && !taggedAlarms[aws.StringValue(alarm.AlarmArn)] {
+				continue
+			}
+			alarms = append(alarms, alarm)
+		}
+		input.NextToken = result.NextToken
+		if result.NextToken == nil {
+			break
+		}
+	}
+	ch <- prometheus.MustNewConstMetric(e.ResourcesTotal, prometheus.GaugeValue, float64(len(alarms)), region)
+
+	var inAlarm float64
+	for _, alarm := range alarms {
+		currentState := aws.StringValue(alarm.StateValue)
+		if currentState == cloudwatch.StateValueAlarm {
+			inAlarm++
+		}
+		// Alarms on metric math expressions have no namespace
+		namespace := aws.StringValue(alarm.Namespace)
+		for _, state := range cloudWatchAlarmStates {
+			if state == currentState {
+				ch <- prometheus.MustNewConstMetric(e.AlarmState, prometheus.GaugeValue, 1, region, aws.StringValue(alarm.AlarmName), namespace, state)
+			} else {
+				ch <- prometheus.MustNewConstMetric(e.AlarmState, prometheus.GaugeValue, 0, region, aws.StringValue(alarm.AlarmName), namespace, state)
+			}
+		}
+	}
+	ch <- prometheus.MustNewConstMetric(e.AlarmsInAlarm, prometheus.GaugeValue, inAlarm, region)
+}
@@ -0,0 +1,305 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// The error code counted in the API errors of a collector when it panics
+const collectorPanicErrorCode = "CollectorPanic"
+
+// Collector is the interface implemented by every AWS resource collector of the exporter
+type Collector interface {
+	prometheus.Collector
+
+	// Name returns the unique name of the collector
+	Name() string
+	// Enabled returns true if the collector has to be registered
+	Enabled() bool
+}
+
+// RefreshingCollector is implemented by the collectors whose AWS calls are too slow or expensive
+// to be made on every scrape, their metrics are cached and refreshed once per interval
+type RefreshingCollector interface {
+	// RefreshInterval returns the minimum duration between two fetches of the metrics from AWS
+	RefreshInterval() time.Duration
+}
+
+// PreflightCollector is implemented by the collectors which can check their IAM permissions
+// at startup with a single cheap AWS call
+type PreflightCollector interface {
+	// Preflight makes the call and returns the IAM permission it requires along with its error
+	Preflight() (string, error)
+}
+
+// GlobalCollector is implemented by the collectors reporting resources which don't belong to a region,
+// they are only created for the first region
+type GlobalCollector interface {
+	// Global returns true, it only marks the collector as global
+	Global() bool
+}
+
+// TagFilteringCollector is implemented by the collectors restricting the resources they export to the ones
+// carrying the --tag.filter tag, a warning is logged at startup for the other ones when a filter is set
+type TagFilteringCollector interface {
+	// FiltersTags returns true, it only marks the collector as applying the tag filter
+	FiltersTags() bool
+}
+
+// ExpectedErrorsCollector is implemented by the collectors handling some AWS errors as a normal outcome, such as
+// a repository without a scanned image, the calls failing with these errors don't fail the collection
+type ExpectedErrorsCollector interface {
+	// ExpectedErrors returns the error codes handled by the collector, by API operation name
+	ExpectedErrors() map[string][]string
+}
+
+// CollectorFactory creates a Collector using the shared AWS session and metrics namespace
+type CollectorFactory func(sess *session.Session, namespace string, logger log.Logger) Collector
+
+var collectorFactories []CollectorFactory
+
+// RegisterCollector adds a collector to the exporter.
+// Collectors living outside of this repository can be compiled in by calling it from an init function.
+func RegisterCollector(factory CollectorFactory) {
+	collectorFactories = append(collectorFactories, factory)
+}
+
+// NewCollectors creates every registered collector for each of the regions,
+// account is the ID of the account of the session when roles are assumed and empty otherwise
+func NewCollectors(sess *session.Session, account string, regions []string, namespace string, logger log.Logger) []Collector {
+	// The registry already runs the collectors concurrently, the slots shared by all the collectors bound how many run at once
+	var slots chan struct{}
+	if *scrapeConcurrency > 0 {
+		slots = make(chan struct{}, *scrapeConcurrency)
+	}
+
+	collectors := make([]Collector, 0, len(collectorFactories))
+	for _, factory := range collectorFactories {
+		// The calls of all the regions are counted together, a collector only succeeds when all its regions did
+		calls := new(callCounts)
+		var first Collector
+		var regional []Collector
+		for _, region := range regions {
+			// The expected errors are only known once the collector is created, before it makes any call
+			expected := map[string]map[string]bool{}
+			collector := factory(countCalls(sess.Copy(aws.NewConfig().WithRegion(region)), account, calls, expected), namespace, logger)
+			if handler, ok := collector.(ExpectedErrorsCollector); ok {
+				for operation, codes := range handler.ExpectedErrors() {
+					expected[operation] = map[string]bool{}
+					for _, code := range codes {
+						expected[operation][code] = true
+					}
+				}
+			}
+			if first == nil {
+				first = collector
+			}
+			regional = append(regional, &recoveringCollector{
+				Collector: collector,
+				slots:     slots,
+				calls:     calls,
+				logger:    logger,
+			})
+			if _, ok := collector.(GlobalCollector); ok {
+				break
+			}
+		}
+		interval := refreshInterval(first)
+		if _, ok := first.(TagFilteringCollector); tagFilter != nil && first.Enabled() && !ok {
+			level.Warn(logger).Log("msg", "Collector doesn't support the tag filter, all its resources are exported", "collector", first.Name(), "filter", tagFilter.Key+"="+tagFilter.Value)
+		}
+
+		var wrapped Collector = &regionsCollector{
+			Collector: regional[0],
+			account:   account,
+			regional:  regional,
+			calls:     calls,
+		}
+		if interval > 0 {
+			wrapped = newCachingCollector(wrapped, interval)
+		}
+		// The permissions are the same in every region, they are only checked in the first one
+		if *preflightCheck && first.Enabled() && !preflight(first, logger) && *preflightDisable {
+			wrapped = &disabledCollector{Collector: wrapped}
+		}
+		collectors = append(collectors, wrapped)
+	}
+	return collectors
+}
+
+// callCounts counts the AWS calls of a collector which succeeded and failed after all their retries
+type callCounts struct {
+	succeeded uint64
+	failed    uint64
+}
+
+// countCalls adds a handler to the session counting the AWS calls which succeeded and failed after all their retries.
+// The calls failing with an error code the collector expects for the operation are counted as succeeded,
+// and the ones expecting the region to be unavailable record whether it is in region_unavailable.
+// Every collector gets its own copy of the session, so that its calls are not mixed up with the ones of the other collectors.
+func countCalls(sess *session.Session, account string, calls *callCounts, expected map[string]map[string]bool) *session.Session {
+	sess.Handlers.Complete.PushBackNamed(request.NamedHandler{
+		Name: "exporter.CountCalls",
+		Fn: func(r *request.Request) {
+			var codes map[string]bool
+			if r.Operation != nil {
+				codes = expected[r.Operation.Name]
+			}
+			if r.Error != nil {
+				if aerr, ok := r.Error.(awserr.Error); !ok || !codes[aerr.Code()] {
+					atomic.AddUint64(&calls.failed, 1)
+					return
+				}
+			}
+			atomic.AddUint64(&calls.succeeded, 1)
+			for code := range codes {
+				if regionUnavailableErrorCodes[code] {
+					exporterMetrics.SetRegionUnavailable(account, aws.StringValue(r.Config.Region), IsRegionUnavailable(r.Error))
+					break
+				}
+			}
+		},
+	})
+	return sess
+}
+
+// preflight runs the preflight check of the collector, it returns false if the collector is missing an IAM permission.
+// Other errors, such as throttling, don't tell anything about the permissions and are only logged.
+func preflight(collector Collector, logger log.Logger) bool {
+	checker, ok := collector.(PreflightCollector)
+	if !ok {
+		return true
+	}
+	permission, err := checker.Preflight()
+	if err == nil {
+		return true
+	}
+	if IsAccessDenied(err) {
+		level.Warn(logger).Log("msg", "Collector is missing an IAM permission", "collector", collector.Name(), "permission", permission, "disabled", *preflightDisable, "err", err)
+		return false
+	}
+	level.Warn(logger).Log("msg", "Preflight check of the collector failed", "collector", collector.Name(), "permission", permission, "err", err)
+	return true
+}
+
+// disabledCollector disables a collector which is missing an IAM permission
+type disabledCollector struct {
+	Collector
+}
+
+// Enabled returns false, the collector is never registered
+func (c *disabledCollector) Enabled() bool {
+	return false
+}
+
+// sessionRegion returns the region of the session the collectors report in their aws_region label,
+// or "unknown" when the session was created without one
+func sessionRegion(sess *session.Session) string {
+	if sess.Config.Region == nil {
+		return "unknown"
+	}
+	return *sess.Config.Region
+}
+
+// refreshInterval returns the interval configured with --scrape.interval for the collector,
+// falling back to the one declared by the collector itself
+func refreshInterval(collector Collector) time.Duration {
+	if interval, ok := scrapeIntervals[collector.Name()]; ok {
+		return interval
+	}
+	if refreshing, ok := collector.(RefreshingCollector); ok {
+		return refreshing.RefreshInterval()
+	}
+	return 0
+}
+
+// ParseScrapeIntervals parses the collector=duration refresh intervals of the collectors
+func ParseScrapeIntervals(flags []string) (map[string]time.Duration, error) {
+	intervals := map[string]time.Duration{}
+	for _, flag := range flags {
+		parts := strings.SplitN(flag, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, fmt.Errorf("invalid scrape interval %q, expected collector=duration", flag)
+		}
+		interval, err := time.ParseDuration(parts[1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid duration in scrape interval %q: %v", flag, err)
+		}
+		intervals[parts[0]] = interval
+	}
+	return intervals, nil
+}
+
+// recoveringCollector recovers from the panics of the wrapped collector so that a single
+// failing collector doesn't take down the whole scrape, a panic is counted as a failed call.
+// Panics in goroutines started by the collector itself are not recovered.
+// When slots is set, the collector waits for a free slot before collecting.
+type recoveringCollector struct {
+	Collector
+
+	slots  chan struct{}
+	calls  *callCounts
+	logger log.Logger
+}
+
+// Collect is used by the Prometheus client to collect and return the metrics values
+func (c *recoveringCollector) Collect(ch chan<- prometheus.Metric) {
+	if c.slots != nil {
+		c.slots <- struct{}{}
+		defer func() { <-c.slots }()
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			level.Error(c.logger).Log("msg", "Collector panicked", "collector", c.Name(), "panic", r)
+			exporterMetrics.IncrementErrors(c.Name(), "Collect", awserr.New(collectorPanicErrorCode, fmt.Sprint(r), nil))
+			atomic.AddUint64(&c.calls.failed, 1)
+		}
+	}()
+	c.Collector.Collect(ch)
+}
+
+// regionsCollector runs the collectors of the same kind created for every region as a single collector.
+// A collection which had no failed AWS call in any region is marked as succeeded,
+// and the collector of the account is ready once a collection had a successful one.
+type regionsCollector struct {
+	Collector
+
+	account  string
+	regional []Collector
+	calls    *callCounts
+}
+
+// Collect is used by the Prometheus client to collect and return the metrics values
+func (c *regionsCollector) Collect(ch chan<- prometheus.Metric) {
+	succeeded := atomic.LoadUint64(&c.calls.succeeded)
+	failed := atomic.LoadUint64(&c.calls.failed)
+	start := time.Now()
+	var wg sync.WaitGroup
+	for _, collector := range c.regional {
+		wg.Add(1)
+		go func(collector Collector) {
+			defer wg.Done()
+			collector.Collect(ch)
+		}(collector)
+	}
+	wg.Wait()
+	exporterMetrics.ObserveDuration(c.account, c.Name(), time.Since(start))
+	exporterMetrics.MarkScraped(c.account, c.Name())
+	if atomic.LoadUint64(&c.calls.failed) == failed {
+		exporterMetrics.MarkSucceeded(c.account, c.Name())
+	}
+	if atomic.LoadUint64(&c.calls.succeeded) != succeeded {
+		readiness.MarkReady(c.account, c.Name())
+	}
+}
@@ -0,0 +1,220 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/ecr"
+	"github.com/go-kit/kit/log"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// fakeCollector exports a single test_<name> metric after waiting for delay, or panics when collected if panics is set
+type fakeCollector struct {
+	name   string
+	panics bool
+	delay  time.Duration
+	desc   *prometheus.Desc
+}
+
+func newFakeCollector(name string, panics bool) *fakeCollector {
+	return &fakeCollector{
+		name:   name,
+		panics: panics,
+		desc:   prometheus.NewDesc(prometheus.BuildFQName(defaultNamespace, "", "test_"+name), "Test metric.", nil, nil),
+	}
+}
+
+func (c *fakeCollector) Name() string {
+	return c.name
+}
+
+func (c *fakeCollector) Enabled() bool {
+	return true
+}
+
+func (c *fakeCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+func (c *fakeCollector) Collect(ch chan<- prometheus.Metric) {
+	if c.panics {
+		panic("collector " + c.name + " failed")
+	}
+	time.Sleep(c.delay)
+	ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, 1)
+}
+
+// newTestCollectors creates the given collectors of the account through NewCollectors, wrapped the way main registers them
+func newTestCollectors(account string, fakes ...*fakeCollector) []prometheus.Collector {
+	factories := collectorFactories
+	defer func() { collectorFactories = factories }()
+
+	collectorFactories = nil
+	for _, fake := range fakes {
+		fake := fake
+		RegisterCollector(func(*session.Session, string, log.Logger) Collector {
+			return fake
+		})
+	}
+	sess := session.Must(session.NewSession(aws.NewConfig().WithRegion(testRegion)))
+	var collectors []prometheus.Collector
+	for _, collector := range NewCollectors(sess, account, []string{testRegion}, defaultNamespace, log.NewNopLogger()) {
+		collectors = append(collectors, collector)
+	}
+	return collectors
+}
+
+func TestRecoveringCollector(t *testing.T) {
+	panicErrors := fmt.Sprintf("%s_api_errors_total{error_code=%q,operation=%q,service=%q}", defaultNamespace, collectorPanicErrorCode, "Collect", "panicking")
+	before := collectSamples(t, exporterMetrics)[panicErrors]
+
+	samples := collectSamples(t, newTestCollectors("",
+		newFakeCollector("first", false),
+		newFakeCollector("panicking", true),
+		newFakeCollector("last", false),
+	)...)
+
+	for _, name := range []string{"first", "last"} {
+		if got := samples[defaultNamespace+"_test_"+name+"{}"]; got != 1 {
+			t.Errorf("metric of collector %s = %v, want 1", name, got)
+		}
+	}
+	if got := countSamples(samples, defaultNamespace+"_test_panicking"); got != 0 {
+		t.Errorf("got %d samples of the panicking collector, want 0", got)
+	}
+	if got := collectSamples(t, exporterMetrics)[panicErrors] - before; got != 1 {
+		t.Errorf("%s increased by %v, want 1", panicErrors, got)
+	}
+}
+
+func TestCollectorsAccounts(t *testing.T) {
+	accounts := []string{"111111111111", "222222222222"}
+	registry := prometheus.NewRegistry()
+	for _, account := range accounts {
+		registerer := prometheus.WrapRegistererWith(prometheus.Labels{"aws_account_id": account}, registry)
+		for _, collector := range newTestCollectors(account, newFakeCollector("accounts", false)) {
+			if err := registerer.Register(collector); err != nil {
+				t.Fatalf("Register() error = %v", err)
+			}
+		}
+	}
+	if _, err := registry.Gather(); err != nil {
+		t.Fatalf("Gather() error = %v", err)
+	}
+
+	samples := collectSamples(t, exporterMetrics)
+	for _, account := range accounts {
+		scraped := fmt.Sprintf("%s_last_scrape_timestamp{aws_account_id=%q,collector=%q}", defaultNamespace, account, "accounts")
+		if samples[scraped] == 0 {
+			t.Errorf("%s is not set", scraped)
+		}
+	}
+}
+
+// newErrorServer returns a server answering every AWS JSON call with the error code
+func newErrorServer(code string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/x-amz-json-1.1")
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprintf(w, `{"__type":%q,"message":"Test error"}`, code)
+	}))
+}
+
+// newTestSession returns a session sending its calls to the server, without retries
+func newTestSession(server *httptest.Server) *session.Session {
+	return session.Must(session.NewSession(aws.NewConfig().
+		WithRegion(testRegion).
+		WithEndpoint(server.URL).
+		WithCredentials(credentials.NewStaticCredentials("id", "secret", "")).
+		WithMaxRetries(0)))
+}
+
+func TestCountCalls(t *testing.T) {
+	// Every call fails with the error of a repository without the tracked image
+	server := newErrorServer(ecr.ErrCodeImageNotFoundException)
+	defer server.Close()
+
+	calls := new(callCounts)
+	expected := map[string]map[string]bool{
+		"DescribeImageScanFindings": {ecr.ErrCodeImageNotFoundException: true},
+	}
+	svc := ecr.New(countCalls(newTestSession(server), "", calls, expected))
+
+	if _, err := svc.DescribeImageScanFindings(&ecr.DescribeImageScanFindingsInput{
+		RepositoryName: aws.String("app"),
+		ImageId:        &ecr.ImageIdentifier{ImageTag: aws.String("latest")},
+	}); err == nil {
+		t.Fatal("DescribeImageScanFindings() succeeded, want an error")
+	}
+	if got := atomic.LoadUint64(&calls.failed); got != 0 {
+		t.Errorf("failed calls after an expected error = %d, want 0", got)
+	}
+	if got := atomic.LoadUint64(&calls.succeeded); got != 1 {
+		t.Errorf("succeeded calls after an expected error = %d, want 1", got)
+	}
+
+	// The same error code is a failure for the operations which don't expect it
+	if _, err := svc.DescribeRepositories(&ecr.DescribeRepositoriesInput{}); err == nil {
+		t.Fatal("DescribeRepositories() succeeded, want an error")
+	}
+	if got := atomic.LoadUint64(&calls.failed); got != 1 {
+		t.Errorf("failed calls after an unexpected error = %d, want 1", got)
+	}
+}
+
+func TestCountCallsRegionUnavailable(t *testing.T) {
+	server := newErrorServer("OptInRequired")
+	defer server.Close()
+
+	const account = "111111111111"
+	expected := map[string]map[string]bool{
+		"DescribeRepositories": regionUnavailableErrorCodes,
+	}
+	svc := ecr.New(countCalls(newTestSession(server), account, new(callCounts), expected))
+	if _, err := svc.DescribeRepositories(&ecr.DescribeRepositoriesInput{}); err == nil {
+		t.Fatal("DescribeRepositories() succeeded, want an error")
+	}
+
+	unavailable := fmt.Sprintf("%s_region_unavailable{aws_account_id=%q,region=%q}", defaultNamespace, account, testRegion)
+	if got := collectSamples(t, exporterMetrics)[unavailable]; got != 1 {
+		t.Errorf("%s = %v, want 1", unavailable, got)
+	}
+}
+
+// BenchmarkCollectors gathers collectors which each wait for an AWS call, with different scrape concurrencies
+func BenchmarkCollectors(b *testing.B) {
+	const collectors = 20
+	concurrency := *scrapeConcurrency
+	defer func() { *scrapeConcurrency = concurrency }()
+
+	for _, workers := range []int{1, 4, 0} {
+		b.Run(fmt.Sprintf("concurrency=%d", workers), func(b *testing.B) {
+			*scrapeConcurrency = workers
+			fakes := make([]*fakeCollector, 0, collectors)
+			for i := 0; i < collectors; i++ {
+				fake := newFakeCollector(fmt.Sprintf("collector%d", i), false)
+				fake.delay = 10 * time.Millisecond
+				fakes = append(fakes, fake)
+			}
+			registry := prometheus.NewRegistry()
+			for _, collector := range newTestCollectors("", fakes...) {
+				registry.MustRegister(collector)
+			}
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := registry.Gather(); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
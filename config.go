@@ -0,0 +1,204 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/prometheus/common/model"
+	"gopkg.in/alecthomas/kingpin.v2"
+	"gopkg.in/yaml.v2"
+)
+
+// collectorFlagPrefix prefixes the flag enabling each collector, e.g. --collector.rds
+const collectorFlagPrefix = "collector."
+
+// Config holds the exporter settings read from --config.file. Every setting maps to a flag,
+// a flag set on the command line overrides the setting of the file.
+type Config struct {
+	// Regions to collect the resources of, like the repeated --aws.region flag
+	Regions []string `yaml:"regions"`
+	// AssumeRoles are the roles of the other accounts to collect, like the repeated --aws.assume-role flag
+	AssumeRoles []AssumeRoleConfig `yaml:"assume_roles"`
+	// Collectors are the names of the enabled collectors, the other collectors are disabled
+	Collectors []string `yaml:"collectors"`
+	// ScrapeIntervals is the minimum interval between two fetches of the metrics of a collector, by collector name
+	ScrapeIntervals map[string]model.Duration `yaml:"scrape_intervals"`
+	// TagFilter only exports the resources carrying this tag
+	TagFilter *TagFilterConfig `yaml:"tag_filter"`
+	RDS       RDSConfig        `yaml:"rds"`
+}
+
+// AssumeRoleConfig is a role to assume with its optional external ID, like --aws.assume-role role-arn[,external-id]
+type AssumeRoleConfig struct {
+	RoleARN    string `yaml:"role_arn"`
+	ExternalID string `yaml:"external_id"`
+}
+
+// TagFilterConfig is the tag the exported resources have to carry, like --tag.filter
+type TagFilterConfig struct {
+	Key   string `yaml:"key"`
+	Value string `yaml:"value"`
+}
+
+// RDSConfig scopes the exported RDS instances, like the --rds.include, --rds.exclude, --rds.engines and --rds.filter flags
+type RDSConfig struct {
+	Include string              `yaml:"include"`
+	Exclude string              `yaml:"exclude"`
+	Engines []string            `yaml:"engines"`
+	Filters map[string][]string `yaml:"filters"`
+}
+
+// LoadConfig reads and validates the YAML configuration file, unknown fields are rejected
+func LoadConfig(path string) (*Config, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	config := &Config{}
+	if err := yaml.UnmarshalStrict(content, config); err != nil {
+		return nil, fmt.Errorf("invalid configuration file %s: %v", path, err)
+	}
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid configuration file %s: %v", path, err)
+	}
+	return config, nil
+}
+
+// Validate checks the settings which can be checked without the flags
+func (c *Config) Validate() error {
+	for _, region := range c.Regions {
+		if region == "" {
+			return fmt.Errorf("empty region")
+		}
+	}
+	for _, role := range c.AssumeRoles {
+		if role.RoleARN == "" || strings.Contains(role.RoleARN, ",") {
+			return fmt.Errorf("invalid role ARN %q of an assumed role", role.RoleARN)
+		}
+	}
+	for _, name := range c.Collectors {
+		if name == "" {
+			return fmt.Errorf("empty collector name")
+		}
+	}
+	for name, interval := range c.ScrapeIntervals {
+		if interval <= 0 {
+			return fmt.Errorf("scrape interval of collector %s has to be positive", name)
+		}
+	}
+	if c.TagFilter != nil && c.TagFilter.Key == "" {
+		return fmt.Errorf("tag filter without a key")
+	}
+	for _, expr := range []string{c.RDS.Include, c.RDS.Exclude} {
+		if _, err := regexp.Compile(expr); err != nil {
+			return fmt.Errorf("invalid RDS instance regular expression %q: %v", expr, err)
+		}
+	}
+	for _, engine := range c.RDS.Engines {
+		if engine == "" || strings.Contains(engine, ",") {
+			return fmt.Errorf("invalid RDS engine %q", engine)
+		}
+	}
+	for name, values := range c.RDS.Filters {
+		if name == "" || len(values) == 0 {
+			return fmt.Errorf("invalid RDS filter %q, expected a name and at least one value", name)
+		}
+	}
+	return nil
+}
+
+// flagValues returns the values of the flags matching the settings of the file, keyed by flag name.
+// Collector names are checked against the collector flags of the application.
+func (c *Config) flagValues(app *kingpin.Application) (map[string][]string, error) {
+	collectors := map[string]bool{}
+	for _, flag := range app.Model().Flags {
+		if strings.HasPrefix(flag.Name, collectorFlagPrefix) {
+			collectors[strings.TrimPrefix(flag.Name, collectorFlagPrefix)] = true
+		}
+	}
+
+	values := map[string][]string{}
+	if len(c.Regions) > 0 {
+		values["aws.region"] = c.Regions
+	}
+	for _, role := range c.AssumeRoles {
+		value := role.RoleARN
+		if role.ExternalID != "" {
+			value += "," + role.ExternalID
+		}
+		values["aws.assume-role"] = append(values["aws.assume-role"], value)
+	}
+	if c.Collectors != nil {
+		enabled := map[string]bool{}
+		for _, name := range c.Collectors {
+			if !collectors[name] {
+				return nil, fmt.Errorf("unknown collector %s", name)
+			}
+			enabled[name] = true
+		}
+		for name := range collectors {
+			values[collectorFlagPrefix+name] = []string{fmt.Sprint(enabled[name])}
+		}
+	}
+	intervals := make([]string, 0, len(c.ScrapeIntervals))
+	for name, interval := range c.ScrapeIntervals {
+		if !collectors[name] {
+			return nil, fmt.Errorf("scrape interval of unknown collector %s", name)
+		}
+		intervals = append(intervals, name+"="+interval.String())
+	}
+	sort.Strings(intervals)
+	values["scrape.interval"] = intervals
+	if c.TagFilter != nil {
+		values["tag.filter"] = []string{c.TagFilter.Key + "=" + c.TagFilter.Value}
+	}
+	if c.RDS.Include != "" {
+		values["rds.include"] = []string{c.RDS.Include}
+	}
+	if c.RDS.Exclude != "" {
+		values["rds.exclude"] = []string{c.RDS.Exclude}
+	}
+	if len(c.RDS.Engines) > 0 {
+		values["rds.engines"] = []string{strings.Join(c.RDS.Engines, ",")}
+	}
+	filters := make([]string, 0, len(c.RDS.Filters))
+	for name, filterValues := range c.RDS.Filters {
+		filters = append(filters, name+"="+strings.Join(filterValues, ","))
+	}
+	sort.Strings(filters)
+	values["rds.filter"] = filters
+	return values, nil
+}
+
+// Apply sets the flags of the application from the settings of the file,
+// except for the flags set in args, the command line the application was parsed from
+func (c *Config) Apply(app *kingpin.Application, args []string) error {
+	values, err := c.flagValues(app)
+	if err != nil {
+		return err
+	}
+	context, err := app.ParseContext(args)
+	if err != nil {
+		return err
+	}
+	userFlags := map[string]bool{}
+	for _, element := range context.Elements {
+		if flag, ok := element.Clause.(*kingpin.FlagClause); ok {
+			userFlags[flag.Model().Name] = true
+		}
+	}
+	for _, flag := range app.Model().Flags {
+		if userFlags[flag.Name] {
+			continue
+		}
+		for _, value := range values[flag.Name] {
+			if err := flag.Value.Set(value); err != nil {
+				return fmt.Errorf("invalid value %q of --%s: %v", value, flag.Name, err)
+			}
+		}
+	}
+	return nil
+}
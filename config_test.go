@@ -0,0 +1,127 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"gopkg.in/alecthomas/kingpin.v2"
+)
+
+// writeConfig writes the content in a configuration file of a temporary directory and returns its path
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+	dir, err := ioutil.TempDir("", "config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	path := filepath.Join(dir, "config.yml")
+	if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr bool
+	}{
+		{
+			name: "valid",
+			content: `
+regions: [us-east-1, eu-west-1]
+assume_roles:
+  - role_arn: arn:aws:iam::111111111111:role/exporter
+  - role_arn: arn:aws:iam::222222222222:role/exporter
+    external_id: my-external-id
+collectors: [ec2, rds]
+scrape_intervals:
+  ec2: 10m
+tag_filter:
+  key: team
+  value: sre
+rds:
+  include: ^prod-
+  engines: [postgres]
+  filters:
+    db-cluster-id: [main]
+`,
+		},
+		{name: "unknown field", content: "region: us-east-1", wantErr: true},
+		{name: "empty region", content: `regions: [""]`, wantErr: true},
+		{name: "assumed role without an ARN", content: "assume_roles: [{external_id: my-external-id}]", wantErr: true},
+		{name: "tag filter without a key", content: "tag_filter: {value: sre}", wantErr: true},
+		{name: "invalid RDS regular expression", content: "rds: {exclude: '('}", wantErr: true},
+		{name: "RDS filter without a value", content: "rds: {filters: {engine: []}}", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadConfig(writeConfig(t, tt.content))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("LoadConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestConfigApply(t *testing.T) {
+	app := kingpin.New("test", "")
+	regions := app.Flag("aws.region", "").Strings()
+	assumeRoles := app.Flag("aws.assume-role", "").Strings()
+	ec2 := app.Flag("collector.ec2", "").Default("false").Bool()
+	rds := app.Flag("collector.rds", "").Default("true").Bool()
+	s3 := app.Flag("collector.s3", "").Default("false").Bool()
+	intervals := app.Flag("scrape.interval", "").Strings()
+	engines := app.Flag("rds.engines", "").String()
+
+	// The regions and the s3 collector set on the command line override the file
+	args := []string{"--aws.region=us-west-2", "--no-collector.s3"}
+	if _, err := app.Parse(args); err != nil {
+		t.Fatal(err)
+	}
+	config, err := LoadConfig(writeConfig(t, `
+regions: [us-east-1, eu-west-1]
+assume_roles:
+  - role_arn: arn:aws:iam::111111111111:role/exporter
+  - role_arn: arn:aws:iam::222222222222:role/exporter
+    external_id: my-external-id
+collectors: [ec2, s3]
+scrape_intervals:
+  s3: 6h
+rds:
+  engines: [postgres, mysql]
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := config.Apply(app, args); err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+
+	if want := []string{"us-west-2"}; !reflect.DeepEqual(*regions, want) {
+		t.Errorf("regions = %v, want %v", *regions, want)
+	}
+	wantRoles := []string{"arn:aws:iam::111111111111:role/exporter", "arn:aws:iam::222222222222:role/exporter,my-external-id"}
+	if !reflect.DeepEqual(*assumeRoles, wantRoles) {
+		t.Errorf("assumed roles = %v, want %v", *assumeRoles, wantRoles)
+	}
+	if !*ec2 || *rds || *s3 {
+		t.Errorf("collectors ec2 = %v, rds = %v, s3 = %v, want true, false, false", *ec2, *rds, *s3)
+	}
+	if want := []string{"s3=6h"}; !reflect.DeepEqual(*intervals, want) {
+		t.Errorf("scrape intervals = %v, want %v", *intervals, want)
+	}
+	if want := "postgres,mysql"; *engines != want {
+		t.Errorf("RDS engines = %q, want %q", *engines, want)
+	}
+
+	config.Collectors = []string{"unknown"}
+	if err := config.Apply(app, args); err == nil {
+		t.Error("Apply() of an unknown collector succeeded, want an error")
+	}
+}
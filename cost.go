@@ -0,0 +1,140 @@
+package main
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/costexplorer"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Cost Explorer is a global service served from us-east-1, the costs are reported in the costRegion region label
+const (
+	costRegion    = "global"
+	costAPIRegion = "us-east-1"
+)
+
+// Cost Explorer charges every request and only updates the costs a few times a day
+const costRefreshInterval = 6 * time.Hour
+
+// The cost metric exported by the collector, in USD
+const costMetric = "UnblendedCost"
+
+// CostExporter defines an instance of the Cost Explorer Exporter
+type CostExporter struct {
+	sess             *session.Session
+	EstimatedCostUSD *prometheus.Desc
+
+	enabled bool
+	logger  log.Logger
+}
+
+func init() {
+	RegisterCollector(func(sess *session.Session, namespace string, logger log.Logger) Collector {
+		return NewCostExporter(sess, namespace, logger, *costEnabled)
+	})
+}
+
+// NewCostExporter creates a new CostExporter instance
+func NewCostExporter(sess *session.Session, namespace string, logger log.Logger, enabled bool) *CostExporter {
+	return &CostExporter{
+		sess:    sess,
+		enabled: enabled,
+		EstimatedCostUSD: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "estimated_cost_usd"),
+			"The unblended cost of the service since the beginning of the month, in USD.",
+			[]string{"aws_region", "service"},
+			nil,
+		),
+		logger: logger,
+	}
+}
+
+// Name returns the name of the collector
+func (e *CostExporter) Name() string {
+	return "cost"
+}
+
+// Enabled returns true if the collector has to be registered
+func (e *CostExporter) Enabled() bool {
+	return e.enabled
+}
+
+// Global returns true, the collector is only created for the first region
+func (e *CostExporter) Global() bool {
+	return true
+}
+
+// RefreshInterval returns the minimum duration between two fetches of the costs
+func (e *CostExporter) RefreshInterval() time.Duration {
+	return costRefreshInterval
+}
+
+// Describe is used by the Prometheus client to return a description of the metrics
+func (e *CostExporter) Describe(ch chan<- *prometheus.Desc) {
+	ch <- e.EstimatedCostUSD
+}
+
+// Collect is used by the Prometheus client to collect and return the metrics values
+func (e *CostExporter) Collect(ch chan<- prometheus.Metric) {
+	svc := costexplorer.New(e.sess, aws.NewConfig().WithRegion(costAPIRegion))
+
+	// The end date is exclusive, ending tomorrow includes today and keeps the period valid on the first day of the month
+	now := time.Now().UTC()
+	start := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	end := now.AddDate(0, 0, 1)
+	input := &costexplorer.GetCostAndUsageInput{
+		Granularity: aws.String(costexplorer.GranularityMonthly),
+		Metrics:     []*string{aws.String(costMetric)},
+		GroupBy: []*costexplorer.GroupDefinition{{
+			Type: aws.String(costexplorer.GroupDefinitionTypeDimension),
+			Key:  aws.String(costexplorer.DimensionService),
+		}},
+		TimePeriod: &costexplorer.DateInterval{
+			Start: aws.String(start.Format("2006-01-02")),
+			End:   aws.String(end.Format("2006-01-02")),
+		},
+	}
+
+	// Get the cost of all the services.
+	// If a NextPageToken is found, do pagination until last page
+	costs := map[string]float64{}
+	for {
+		exporterMetrics.IncrementRequests(costexplorer.ServiceName, "GetCostAndUsage")
+		result, err := svc.GetCostAndUsage(input)
+		if err != nil {
+			level.Error(e.logger).Log("msg", "Call to GetCostAndUsage failed", "region", costRegion, "err", err)
+			exporterMetrics.IncrementErrors(costexplorer.ServiceName, "GetCostAndUsage", err)
+			return
+		}
+		for _, period := range result.ResultsByTime {
+			for _, group := range period.Groups {
+				if len(group.Keys) == 0 {
+					continue
+				}
+				cost, ok := group.Metrics[costMetric]
+				if !ok {
+					continue
+				}
+				amount, err := strconv.ParseFloat(aws.StringValue(cost.Amount), 64)
+				if err != nil {
+					level.Warn(e.logger).Log("msg", "Could not parse the cost", "service", aws.StringValue(group.Keys[0]), "amount", aws.StringValue(cost.Amount), "err", err)
+					continue
+				}
+				costs[aws.StringValue(group.Keys[0])] += amount
+			}
+		}
+		input.NextPageToken = result.NextPageToken
+		if result.NextPageToken == nil {
+			break
+		}
+	}
+
+	for service, cost := range costs {
+		ch <- prometheus.MustNewConstMetric(e.EstimatedCostUSD, prometheus.GaugeValue, cost, costRegion, service)
+	}
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/databasemigrationservice"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// DMSExporter defines an instance of the DMS Exporter
+type DMSExporter struct {
+	sess                      *session.Session
+	ReplicationTaskFullLoaded *prometheus.Desc
+	ReplicationTaskProgress   *prometheus.Desc
+	ReplicationTaskStatus     *prometheus.Desc
+	ResourcesTotal            *prometheus.Desc
+
+	enabled bool
+	logger  log.Logger
+}
+
+func init() {
+	RegisterCollector(func(sess *session.Session, namespace string, logger log.Logger) Collector {
+		return NewDMSExporter(sess, namespace, logger, *dmsEnabled)
+	})
+}
+
+// NewDMSExporter creates a new DMSExporter instance
+func NewDMSExporter(sess *session.Session, namespace string, logger log.Logger, enabled bool) *DMSExporter {
+	return &DMSExporter{
+		sess:    sess,
+		enabled: enabled,
+		ReplicationTaskFullLoaded: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "dms_replication_task_full_load_finished"),
+			"Indicates if the full load of the replication task is finished.",
+			[]string{"aws_region", "replication_task_id"},
+			nil,
+		),
+		ReplicationTaskProgress: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "dms_replication_task_progress_percent"),
+			"The percent complete of the full load of the replication task.",
+			[]string{"aws_region", "replication_task_id"},
+			nil,
+		),
+		ReplicationTaskStatus: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "dms_replication_task_status"),
+			"The status of the replication task. The value is always 1.",
+			[]string{"aws_region", "replication_task_id", "status"},
+			nil,
+		),
+		ResourcesTotal: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "dms_resources_total"),
+			"The number of DMS replication tasks listed by the collector.",
+			[]string{"aws_region"},
+			nil,
+		),
+		logger: logger,
+	}
+}
+
+// Name returns the name of the collector
+func (e *DMSExporter) Name() string {
+	return "dms"
+}
+
+// Enabled returns true if the collector has to be registered
+func (e *DMSExporter) Enabled() bool {
+	return e.enabled
+}
+
+// FiltersTags returns true, the collector applies the tag filter
+func (e *DMSExporter) FiltersTags() bool {
+	return true
+}
+
+// Preflight checks the IAM permissions of the collector with a single call
+func (e *DMSExporter) Preflight() (string, error) {
+	_, err := databasemigrationservice.New(e.sess).DescribeReplicationTasks(&databasemigrationservice.DescribeReplicationTasksInput{MaxRecords: aws.Int64(20)})
+	return "dms:DescribeReplicationTasks", err
+}
+
+// Describe is used by the Prometheus client to return a description of the metrics
+func (e *DMSExporter) Describe(ch chan<- *prometheus.Desc) {
+	ch <- e.ReplicationTaskFullLoaded
+	ch <- e.ReplicationTaskProgress
+	ch <- e.ReplicationTaskStatus
+	ch <- e.ResourcesTotal
+}
+
+// Collect is used by the Prometheus client to collect and return the metrics values
+func (e *DMSExporter) Collect(ch chan<- prometheus.Metric) {
+	region := sessionRegion(e.sess)
+	svc := databasemigrationservice.New(e.sess)
+
+	// The Resource Groups Tagging API filters the replication tasks on their tags
+	var taggedTasks map[string]bool
+	if tagFilter != nil {
+		arns, err := tagFilter.ResourceARNs(e.sess, "dms:task")
+		if err != nil {
+			level.Error(e.logger).Log("msg", "Call to GetResources failed", "region", region, "err", err)
+			return
+		}
+		taggedTasks = arns
+	}
+
+	input := &databasemigrationservice.DescribeReplicationTasksInput{
+		WithoutSettings: aws.Bool(true),
+	}
+
+	// Get all replication tasks.
+	// If a Marker is found, do pagination until last page
+	var tasks []*databasemigrationservice.ReplicationTask
+	for {
+		exporterMetrics.IncrementRequests(databasemigrationservice.ServiceName, "DescribeReplicationTasks")
+		result, err := svc.DescribeReplicationTasks(input)
+		if err != nil {
+			level.Error(e.logger).Log("msg", "Call to DescribeReplicationTasks failed", "region", region, "err", err)
+			exporterMetrics.IncrementErrors(databasemigrationservice.ServiceName, "DescribeReplicationTasks", err)
+			return
+		}
+		for _, task := range result.ReplicationTasks {
+			if taggedTasks != nil && !taggedTasks[aws.StringValue(task.ReplicationTaskArn)] {
+				continue
+			}
+			tasks = append(tasks, task)
+		}
+		input.Marker = result.Marker
+		if result.Marker == nil {
+			break
+		}
+	}
+	ch <- prometheus.MustNewConstMetric(e.ResourcesTotal, prometheus.GaugeValue, float64(len(tasks)), region)
+
+	for _, task := range tasks {
+		taskID := aws.StringValue(task.ReplicationTaskIdentifier)
+		ch <- prometheus.MustNewConstMetric(e.ReplicationTaskStatus, prometheus.GaugeValue, 1, region, taskID, aws.StringValue(task.Status))
+
+		// The statistics are not set until the task starts
+		if task.ReplicationTaskStats == nil {
+			continue
+		}
+		if task.ReplicationTaskStats.FullLoadProgressPercent != nil {
+			ch <- prometheus.MustNewConstMetric(e.ReplicationTaskProgress, prometheus.GaugeValue, float64(*task.ReplicationTaskStats.FullLoadProgressPercent), region, taskID)
+		}
+		if task.ReplicationTaskStats.FullLoadFinishDate != nil {
+			ch <- prometheus.MustNewConstMetric(e.ReplicationTaskFullLoaded, prometheus.GaugeValue, 1, region, taskID)
+		} else {
+			ch <- prometheus.MustNewConstMetric(e.ReplicationTaskFullLoaded, prometheus.GaugeValue, 0, region, taskID)
+		}
+	}
+}
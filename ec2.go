@@ -0,0 +1,245 @@
+package main
+
+import (
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// The number of image IDs looked up per DescribeImages call, within the limit of values of a filter
+const ec2DescribeImagesBatchSize = 100
+
+// EC2Exporter defines an instance of the EC2 Exporter
+type EC2Exporter struct {
+	sess               *session.Session
+	EIPAssociated      *prometheus.Desc
+	InstanceAMIAge     *prometheus.Desc
+	InstanceLaunchTime *prometheus.Desc
+	NatGatewayState    *prometheus.Desc
+	ResourcesTotal     *prometheus.Desc
+
+	enabled bool
+	logger  log.Logger
+}
+
+func init() {
+	RegisterCollector(func(sess *session.Session, namespace string, logger log.Logger) Collector {
+		return NewEC2Exporter(sess, namespace, logger, *ec2Enabled)
+	})
+}
+
+// NewEC2Exporter creates a new EC2Exporter instance
+func NewEC2Exporter(sess *session.Session, namespace string, logger log.Logger, enabled bool) *EC2Exporter {
+	return &EC2Exporter{
+		sess:    sess,
+		enabled: enabled,
+		EIPAssociated: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "eip_associated"),
+			"Indicates if the Elastic IP is associated with an instance or a network interface.",
+			[]string{"aws_region", "allocation_id", "public_ip"},
+			nil,
+		),
+		InstanceAMIAge: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "ec2_instance_ami_age_days"),
+			"The age of the AMI the instance was launched from, in days.",
+			[]string{"aws_region", "instance_id", "image_id"},
+			nil,
+		),
+		InstanceLaunchTime: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "ec2_instance_launch_timestamp"),
+			"Unix timestamp of the launch of the instance.",
+			[]string{"aws_region", "instance_id", "image_id"},
+			nil,
+		),
+		NatGatewayState: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "natgateway_state"),
+			"The state of the NAT gateway.",
+			[]string{"aws_region", "nat_gateway_id", "subnet_id", "state"},
+			nil,
+		),
+		ResourcesTotal: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "ec2_resources_total"),
+			"The number of EC2 instances listed by the collector.",
+			[]string{"aws_region"},
+			nil,
+		),
+		logger: logger,
+	}
+}
+
+// Name returns the name of the collector
+func (e *EC2Exporter) Name() string {
+	return "ec2"
+}
+
+// Enabled returns true if the collector has to be registered
+func (e *EC2Exporter) Enabled() bool {
+	return e.enabled
+}
+
+// FiltersTags returns true, the collector applies the tag filter
+func (e *EC2Exporter) FiltersTags() bool {
+	return true
+}
+
+// Preflight checks the IAM permissions of the collector with a single call
+func (e *EC2Exporter) Preflight() (string, error) {
+	_, err := ec2.New(e.sess).DescribeNatGateways(&ec2.DescribeNatGatewaysInput{MaxResults: aws.Int64(5)})
+	return "ec2:DescribeNatGateways", err
+}
+
+// Describe is used by the Prometheus client to return a description of the metrics
+func (e *EC2Exporter) Describe(ch chan<- *prometheus.Desc) {
+	ch <- e.EIPAssociated
+	ch <- e.InstanceAMIAge
+	ch <- e.InstanceLaunchTime
+	ch <- e.NatGatewayState
+	ch <- e.ResourcesTotal
+}
+
+// Collect is used by the Prometheus client to collect and return the metrics values
+func (e *EC2Exporter) Collect(ch chan<- prometheus.Metric) {
+	region := sessionRegion(e.sess)
+	svc := ec2.New(e.sess)
+	e.collectNatGateways(ch, region, svc)
+	e.collectAddresses(ch, region, svc)
+	e.collectInstances(ch, region, svc)
+}
+
+// collectNatGateways collects the state of all the NAT gateways
+func (e *EC2Exporter) collectNatGateways(ch chan<- prometheus.Metric, region string, svc *ec2.EC2) {
+	input := &ec2.DescribeNatGatewaysInput{
+		Filter: tagFilter.EC2Filters(),
+	}
+
+	// Get all NAT gateways.
+	// If a NextToken is found, do pagination until last page
+	var gateways []*ec2.NatGateway
+	for {
+		exporterMetrics.IncrementRequests(ec2.ServiceName, "DescribeNatGateways")
+		result, err := svc.DescribeNatGateways(input)
+		if err != nil {
+			level.Error(e.logger).Log("msg", "Call to DescribeNatGateways failed", "region", region, "err", err)
+			exporterMetrics.IncrementErrors(ec2.ServiceName, "DescribeNatGateways", err)
+			return
+		}
+		gateways = append(gateways, result.NatGateways...)
+		input.NextToken = result.NextToken
+		if result.NextToken == nil {
+			break
+		}
+	}
+
+	for _, gateway := range gateways {
+		ch <- prometheus.MustNewConstMetric(e.NatGatewayState, prometheus.GaugeValue, 1, region, *gateway.NatGatewayId, aws.StringValue(gateway.SubnetId), aws.StringValue(gateway.State))
+	}
+}
+
+// collectAddresses collects the association status of all the Elastic IPs
+func (e *EC2Exporter) collectAddresses(ch chan<- prometheus.Metric, region string, svc *ec2.EC2) {
+	exporterMetrics.IncrementRequests(ec2.ServiceName, "DescribeAddresses")
+	result, err := svc.DescribeAddresses(&ec2.DescribeAddressesInput{
+		Filters: tagFilter.EC2Filters(),
+	})
+	if err != nil {
+		level.Error(e.logger).Log("msg", "Call to DescribeAddresses failed", "region", region, "err", err)
+		exporterMetrics.IncrementErrors(ec2.ServiceName, "DescribeAddresses", err)
+		return
+	}
+
+	for _, address := range result.Addresses {
+		// Unassociated Elastic IPs are billed while they are not attached to anything
+		if address.AssociationId != nil {
+			ch <- prometheus.MustNewConstMetric(e.EIPAssociated, prometheus.GaugeValue, 1, region, aws.StringValue(address.AllocationId), aws.StringValue(address.PublicIp))
+		} else {
+			ch <- prometheus.MustNewConstMetric(e.EIPAssociated, prometheus.GaugeValue, 0, region, aws.StringValue(address.AllocationId), aws.StringValue(address.PublicIp))
+		}
+	}
+}
+
+// collectInstances collects the launch time of all the instances and the age of the AMIs they were launched from
+func (e *EC2Exporter) collectInstances(ch chan<- prometheus.Metric, region string, svc *ec2.EC2) {
+	input := &ec2.DescribeInstancesInput{
+		Filters: append(tagFilter.EC2Filters(), &ec2.Filter{
+			Name:   aws.String("instance-state-name"),
+			Values: aws.StringSlice([]string{"pending", "running", "shutting-down", "stopping", "stopped"}),
+		}),
+	}
+
+	// Get all instances.
+	// If a NextToken is found, do pagination until last page
+	var instances []*ec2.Instance
+	for {
+		exporterMetrics.IncrementRequests(ec2.ServiceName, "DescribeInstances")
+		result, err := svc.DescribeInstances(input)
+		if err != nil {
+			level.Error(e.logger).Log("msg", "Call to DescribeInstances failed", "region", region, "err", err)
+			exporterMetrics.IncrementErrors(ec2.ServiceName, "DescribeInstances", err)
+			return
+		}
+		for _, reservation := range result.Reservations {
+			instances = append(instances, reservation.Instances...)
+		}
+		input.NextToken = result.NextToken
+		if result.NextToken == nil {
+			break
+		}
+	}
+	ch <- prometheus.MustNewConstMetric(e.ResourcesTotal, prometheus.GaugeValue, float64(len(instances)), region)
+
+	// Many instances share the same image, each image is only looked up once per scrape
+	imageCreation := map[string]time.Time{}
+	var imageIDs []string
+	seen := map[string]bool{}
+	for _, instance := range instances {
+		imageID := aws.StringValue(instance.ImageId)
+		if imageID != "" && !seen[imageID] {
+			seen[imageID] = true
+			imageIDs = append(imageIDs, imageID)
+		}
+	}
+	for start := 0; start < len(imageIDs); start += ec2DescribeImagesBatchSize {
+		end := start + ec2DescribeImagesBatchSize
+		if end > len(imageIDs) {
+			end = len(imageIDs)
+		}
+		exporterMetrics.IncrementRequests(ec2.ServiceName, "DescribeImages")
+		// Looking the images up with ImageIds fails the whole batch with InvalidAMIID.NotFound as soon as one of them
+		// was deregistered, the image-id filter omits the missing images instead
+		result, err := svc.DescribeImages(&ec2.DescribeImagesInput{
+			Filters: []*ec2.Filter{{
+				Name:   aws.String("image-id"),
+				Values: aws.StringSlice(imageIDs[start:end]),
+			}},
+		})
+		if err != nil {
+			level.Error(e.logger).Log("msg", "Call to DescribeImages failed", "region", region, "err", err)
+			exporterMetrics.IncrementErrors(ec2.ServiceName, "DescribeImages", err)
+			continue
+		}
+		for _, image := range result.Images {
+			created, err := time.Parse(time.RFC3339, aws.StringValue(image.CreationDate))
+			if err != nil {
+				level.Warn(e.logger).Log("msg", "Could not parse the image creation date", "image", aws.StringValue(image.ImageId), "creation_date", aws.StringValue(image.CreationDate), "err", err)
+				continue
+			}
+			imageCreation[aws.StringValue(image.ImageId)] = created
+		}
+	}
+
+	for _, instance := range instances {
+		imageID := aws.StringValue(instance.ImageId)
+		if instance.LaunchTime != nil {
+			ch <- prometheus.MustNewConstMetric(e.InstanceLaunchTime, prometheus.GaugeValue, float64(instance.LaunchTime.Unix()), region, *instance.InstanceId, imageID)
+		}
+		// Deregistered and no longer shared images are omitted by the image-id filter of DescribeImages
+		if created, ok := imageCreation[imageID]; ok {
+			ch <- prometheus.MustNewConstMetric(e.InstanceAMIAge, prometheus.GaugeValue, time.Since(created).Hours()/24, region, *instance.InstanceId, imageID)
+		}
+	}
+}
@@ -0,0 +1,193 @@
+package main
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/ecr"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// ECRExporter defines an instance of the ECR Exporter
+type ECRExporter struct {
+	sess              *session.Session
+	imageTag          string
+	ImageScanFindings *prometheus.Desc
+	RepositoryImages  *prometheus.Desc
+	ResourcesTotal    *prometheus.Desc
+
+	enabled bool
+	logger  log.Logger
+}
+
+func init() {
+	RegisterCollector(func(sess *session.Session, namespace string, logger log.Logger) Collector {
+		return NewECRExporter(sess, namespace, logger, *ecrEnabled, *ecrImageTag)
+	})
+}
+
+// NewECRExporter creates a new ECRExporter instance reporting the scan findings of the images tagged with imageTag
+func NewECRExporter(sess *session.Session, namespace string, logger log.Logger, enabled bool, imageTag string) *ECRExporter {
+	return &ECRExporter{
+		sess:     sess,
+		imageTag: imageTag,
+		enabled:  enabled,
+		ImageScanFindings: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "ecr_image_scan_findings"),
+			"The number of findings of the latest scan of the image, by severity.",
+			[]string{"aws_region", "repository_name", "image_tag", "severity"},
+			nil,
+		),
+		RepositoryImages: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "ecr_repository_image_count"),
+			"The number of images in the repository.",
+			[]string{"aws_region", "repository_name"},
+			nil,
+		),
+		ResourcesTotal: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "ecr_resources_total"),
+			"The number of ECR repositories listed by the collector.",
+			[]string{"aws_region"},
+			nil,
+		),
+		logger: logger,
+	}
+}
+
+// Name returns the name of the collector
+func (e *ECRExporter) Name() string {
+	return "ecr"
+}
+
+// Enabled returns true if the collector has to be registered
+func (e *ECRExporter) Enabled() bool {
+	return e.enabled
+}
+
+// FiltersTags returns true, the collector applies the tag filter
+func (e *ECRExporter) FiltersTags() bool {
+	return true
+}
+
+// Preflight checks the IAM permissions of the collector with a single call
+func (e *ECRExporter) Preflight() (string, error) {
+	_, err := ecr.New(e.sess).DescribeRepositories(&ecr.DescribeRepositoriesInput{MaxResults: aws.Int64(1)})
+	return "ecr:DescribeRepositories", err
+}
+
+// ExpectedErrors returns the errors of the repositories without the tracked image or without a scan of it
+func (e *ECRExporter) ExpectedErrors() map[string][]string {
+	return map[string][]string{
+		"DescribeImageScanFindings": {ecr.ErrCodeImageNotFoundException, ecr.ErrCodeScanNotFoundException},
+	}
+}
+
+// Describe is used by the Prometheus client to return a description of the metrics
+func (e *ECRExporter) Describe(ch chan<- *prometheus.Desc) {
+	ch <- e.ImageScanFindings
+	ch <- e.RepositoryImages
+	ch <- e.ResourcesTotal
+}
+
+// Collect is used by the Prometheus client to collect and return the metrics values
+func (e *ECRExporter) Collect(ch chan<- prometheus.Metric) {
+	region := sessionRegion(e.sess)
+	svc := ecr.New(e.sess)
+
+	// The Resource Groups Tagging API filters the repositories on their tags
+	var taggedRepositories map[string]bool
+	if tagFilter != nil {
+		arns, err := tagFilter.ResourceARNs(e.sess, "ecr:repository")
+		if err != nil {
+			level.Error(e.logger).Log("msg", "Call to GetResources failed", "region", region, "err", err)
+			return
+		}
+		taggedRepositories = arns
+	}
+
+	input := &ecr.DescribeRepositoriesInput{}
+
+	// Get all repositories.
+	// If a NextToken is found, do pagination until last page
+	var repositories []*ecr.Repository
+	for {
+		exporterMetrics.IncrementRequests(ecr.ServiceName, "DescribeRepositories")
+		result, err := svc.DescribeRepositories(input)
+		if err != nil {
+			level.Error(e.logger).Log("msg", "Call to DescribeRepositories failed", "region", region, "err", err)
+			exporterMetrics.IncrementErrors(ecr.ServiceName, "DescribeRepositories", err)
+			return
+		}
+		for _, repository := range result.Repositories {
+			if taggedRepositories != nil && !taggedRepositories[aws.StringValue(repository.RepositoryArn)] {
+				continue
+			}
+			repositories = append(repositories, repository)
+		}
+		input.NextToken = result.NextToken
+		if result.NextToken == nil {
+			break
+		}
+	}
+	ch <- prometheus.MustNewConstMetric(e.ResourcesTotal, prometheus.GaugeValue, float64(len(repositories)), region)
+
+	for _, repository := range repositories {
+		e.collectImageCount(ch, region, svc, repository)
+		e.collectScanFindings(ch, region, svc, repository)
+	}
+}
+
+// collectImageCount collects the number of images of the repository
+func (e *ECRExporter) collectImageCount(ch chan<- prometheus.Metric, region string, svc *ecr.ECR, repository *ecr.Repository) {
+	input := &ecr.ListImagesInput{RepositoryName: repository.RepositoryName}
+
+	// Get all images of the repository.
+	// If a NextToken is found, do pagination until last page
+	// An image with several tags is listed once per tag, so images are counted by digest
+	digests := map[string]bool{}
+	for {
+		exporterMetrics.IncrementRequests(ecr.ServiceName, "ListImages")
+		result, err := svc.ListImages(input)
+		if err != nil {
+			level.Error(e.logger).Log("msg", "Call to ListImages failed", "region", region, "repository", *repository.RepositoryName, "err", err)
+			exporterMetrics.IncrementErrors(ecr.ServiceName, "ListImages", err)
+			return
+		}
+		for _, image := range result.ImageIds {
+			digests[aws.StringValue(image.ImageDigest)] = true
+		}
+		input.NextToken = result.NextToken
+		if result.NextToken == nil {
+			break
+		}
+	}
+
+	ch <- prometheus.MustNewConstMetric(e.RepositoryImages, prometheus.GaugeValue, float64(len(digests)), region, *repository.RepositoryName)
+}
+
+// collectScanFindings collects the severity counts of the latest scan of the tracked image of the repository
+func (e *ECRExporter) collectScanFindings(ch chan<- prometheus.Metric, region string, svc *ecr.ECR, repository *ecr.Repository) {
+	exporterMetrics.IncrementRequests(ecr.ServiceName, "DescribeImageScanFindings")
+	result, err := svc.DescribeImageScanFindings(&ecr.DescribeImageScanFindingsInput{
+		RepositoryName: repository.RepositoryName,
+		ImageId:        &ecr.ImageIdentifier{ImageTag: aws.String(e.imageTag)},
+	})
+	if err != nil {
+		exporterMetrics.IncrementErrors(ecr.ServiceName, "DescribeImageScanFindings", err)
+		// Not every repository has an image with the tracked tag, nor a scan of it
+		if aerr, ok := err.(awserr.Error); ok && (aerr.Code() == ecr.ErrCodeImageNotFoundException || aerr.Code() == ecr.ErrCodeScanNotFoundException) {
+			return
+		}
+		level.Error(e.logger).Log("msg", "Call to DescribeImageScanFindings failed", "region", region, "repository", *repository.RepositoryName, "err", err)
+		return
+	}
+	if result.ImageScanFindings == nil {
+		return
+	}
+
+	for severity, count := range result.ImageScanFindings.FindingSeverityCounts {
+		ch <- prometheus.MustNewConstMetric(e.ImageScanFindings, prometheus.GaugeValue, float64(aws.Int64Value(count)), region, *repository.RepositoryName, e.imageTag, severity)
+	}
+}
@@ -0,0 +1,213 @@
+package main
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Maximum number of clusters and services of a single DescribeClusters and DescribeServices call
+const (
+	ecsDescribeClustersLimit = 100
+	ecsDescribeServicesLimit = 10
+)
+
+// ECSExporter defines an instance of the ECS Exporter
+type ECSExporter struct {
+	sess                *session.Session
+	ResourcesTotal      *prometheus.Desc
+	ServiceDesiredCount *prometheus.Desc
+	ServicePendingCount *prometheus.Desc
+	ServiceRunningCount *prometheus.Desc
+
+	enabled bool
+	logger  log.Logger
+}
+
+func init() {
+	RegisterCollector(func(sess *session.Session, namespace string, logger log.Logger) Collector {
+		return NewECSExporter(sess, namespace, logger, *ecsEnabled)
+	})
+}
+
+// NewECSExporter creates a new ECSExporter instance
+func NewECSExporter(sess *session.Session, namespace string, logger log.Logger, enabled bool) *ECSExporter {
+	return &ECSExporter{
+		sess:    sess,
+		enabled: enabled,
+		ResourcesTotal: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "ecs_resources_total"),
+			"The number of ECS clusters listed by the collector.",
+			[]string{"aws_region"},
+			nil,
+		),
+		ServiceDesiredCount: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "ecs_service_desired_count"),
+			"The desired number of tasks of the ECS service.",
+			[]string{"aws_region", "cluster_name", "service_name"},
+			nil,
+		),
+		ServicePendingCount: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "ecs_service_pending_count"),
+			"The number of tasks of the ECS service in the PENDING state.",
+			[]string{"aws_region", "cluster_name", "service_name"},
+			nil,
+		),
+		ServiceRunningCount: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "ecs_service_running_count"),
+			"The number of tasks of the ECS service in the RUNNING state.",
+			[]string{"aws_region", "cluster_name", "service_name"},
+			nil,
+		),
+		logger: logger,
+	}
+}
+
+// Name returns the name of the collector
+func (e *ECSExporter) Name() string {
+	return "ecs"
+}
+
+// Enabled returns true if the collector has to be registered
+func (e *ECSExporter) Enabled() bool {
+	return e.enabled
+}
+
+// FiltersTags returns true, the collector applies the tag filter
+func (e *ECSExporter) FiltersTags() bool {
+	return true
+}
+
+// Preflight checks the IAM permissions of the collector with a single call
+func (e *ECSExporter) Preflight() (string, error) {
+	_, err := ecs.New(e.sess).ListClusters(&ecs.ListClustersInput{MaxResults: aws.Int64(1)})
+	return "ecs:ListClusters", err
+}
+
+// Describe is used by the Prometheus client to return a description of the metrics
+func (e *ECSExporter) Describe(ch chan<- *prometheus.Desc) {
+	ch <- e.ResourcesTotal
+	ch <- e.ServiceDesiredCount
+	ch <- e.ServicePendingCount
+	ch <- e.ServiceRunningCount
+}
+
+// Collect is used by the Prometheus client to collect and return the metrics values
+func (e *ECSExporter) Collect(ch chan<- prometheus.Metric) {
+	region := sessionRegion(e.sess)
+	svc := ecs.New(e.sess)
+	input := &ecs.ListClustersInput{}
+
+	// Get all cluster ARNs.
+	// If a NextToken is found, do pagination until last page
+	var clusterArns []*string
+	for {
+		exporterMetrics.IncrementRequests(ecs.ServiceName, "ListClusters")
+		result, err := svc.ListClusters(input)
+		if err != nil {
+			level.Error(e.logger).Log("msg", "Call to ListClusters failed", "region", region, "err", err)
+			exporterMetrics.IncrementErrors(ecs.ServiceName, "ListClusters", err)
+			return
+		}
+		clusterArns = append(clusterArns, result.ClusterArns...)
+		input.NextToken = result.NextToken
+		if result.NextToken == nil {
+			break
+		}
+	}
+
+	// The tags of the clusters are only returned by DescribeClusters, the clusters are filtered before being counted
+	var clusters []*ecs.Cluster
+	for start := 0; start < len(clusterArns); start += ecsDescribeClustersLimit {
+		end := start + ecsDescribeClustersLimit
+		if end > len(clusterArns) {
+			end = len(clusterArns)
+		}
+
+		exporterMetrics.IncrementRequests(ecs.ServiceName, "DescribeClusters")
+		result, err := svc.DescribeClusters(&ecs.DescribeClustersInput{
+			Clusters: clusterArns[start:end],
+			Include:  aws.StringSlice([]string{ecs.ClusterFieldTags}),
+		})
+		if err != nil {
+			level.Error(e.logger).Log("msg", "Call to DescribeClusters failed", "region", region, "err", err)
+			exporterMetrics.IncrementErrors(ecs.ServiceName, "DescribeClusters", err)
+			return
+		}
+		for _, cluster := range result.Clusters {
+			tags := map[string]*string{}
+			for _, tag := range cluster.Tags {
+				tags[aws.StringValue(tag.Key)] = tag.Value
+			}
+			if tagFilter.Includes(tags) {
+				clusters = append(clusters, cluster)
+			}
+		}
+	}
+	ch <- prometheus.MustNewConstMetric(e.ResourcesTotal, prometheus.GaugeValue, float64(len(clusters)), region)
+
+	for _, cluster := range clusters {
+		e.collectServices(ch, region, svc, cluster)
+	}
+}
+
+// collectServices collects the task counts of all the services of the cluster
+func (e *ECSExporter) collectServices(ch chan<- prometheus.Metric, region string, svc *ecs.ECS, cluster *ecs.Cluster) {
+	input := &ecs.ListServicesInput{Cluster: cluster.ClusterArn}
+
+	// Get all service ARNs of the cluster.
+	// If a NextToken is found, do pagination until last page
+	var serviceArns []*string
+	for {
+		exporterMetrics.IncrementRequests(ecs.ServiceName, "ListServices")
+		result, err := svc.ListServices(input)
+		if err != nil {
+			level.Error(e.logger).Log("msg", "Call to ListServices failed", "region", region, "cluster", *cluster.ClusterArn, "err", err)
+			exporterMetrics.IncrementErrors(ecs.ServiceName, "ListServices", err)
+			return
+		}
+		serviceArns = append(serviceArns, result.ServiceArns...)
+		input.NextToken = result.NextToken
+		if result.NextToken == nil {
+			break
+		}
+	}
+
+	clusterName := aws.StringValue(cluster.ClusterName)
+	for start := 0; start < len(serviceArns); start += ecsDescribeServicesLimit {
+		end := start + ecsDescribeServicesLimit
+		if end > len(serviceArns) {
+			end = len(serviceArns)
+		}
+
+		exporterMetrics.IncrementRequests(ecs.ServiceName, "DescribeServices")
+		result, err := svc.DescribeServices(&ecs.DescribeServicesInput{
+			Cluster:  cluster.ClusterArn,
+			Services: serviceArns[start:end],
+			Include:  aws.StringSlice([]string{ecs.ServiceFieldTags}),
+		})
+		if err != nil {
+			level.Error(e.logger).Log("msg", "Call to DescribeServices failed", "region", region, "cluster", *cluster.ClusterArn, "err", err)
+			exporterMetrics.IncrementErrors(ecs.ServiceName, "DescribeServices", err)
+			continue
+		}
+
+		for _, service := range result.Services {
+			tags := map[string]*string{}
+			for _, tag := range service.Tags {
+				tags[aws.StringValue(tag.Key)] = tag.Value
+			}
+			if !tagFilter.Includes(tags) {
+				continue
+			}
+
+			serviceName := aws.StringValue(service.ServiceName)
+			ch <- prometheus.MustNewConstMetric(e.ServiceDesiredCount, prometheus.GaugeValue, float64(aws.Int64Value(service.DesiredCount)), region, clusterName, serviceName)
+			ch <- prometheus.MustNewConstMetric(e.ServiceRunningCount, prometheus.GaugeValue, float64(aws.Int64Value(service.RunningCount)), region, clusterName, serviceName)
+			ch <- prometheus.MustNewConstMetric(e.ServicePendingCount, prometheus.GaugeValue, float64(aws.Int64Value(service.PendingCount)), region, clusterName, serviceName)
+		}
+	}
+}
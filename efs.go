@@ -0,0 +1,137 @@
+package main
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/efs"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// EFSExporter defines an instance of the EFS Exporter
+type EFSExporter struct {
+	sess                 *session.Session
+	LifecycleState       *prometheus.Desc
+	NumberOfMountTargets *prometheus.Desc
+	ResourcesTotal       *prometheus.Desc
+	SizeBytes            *prometheus.Desc
+
+	enabled bool
+	logger  log.Logger
+}
+
+func init() {
+	RegisterCollector(func(sess *session.Session, namespace string, logger log.Logger) Collector {
+		return NewEFSExporter(sess, namespace, logger, *efsEnabled)
+	})
+}
+
+// NewEFSExporter creates a new EFSExporter instance
+func NewEFSExporter(sess *session.Session, namespace string, logger log.Logger, enabled bool) *EFSExporter {
+	return &EFSExporter{
+		sess:    sess,
+		enabled: enabled,
+		LifecycleState: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "efs_lifecycle_state"),
+			"The lifecycle state of the file system.",
+			[]string{"aws_region", "file_system_id", "name", "state"},
+			nil,
+		),
+		NumberOfMountTargets: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "efs_number_of_mount_targets"),
+			"The number of mount targets of the file system.",
+			[]string{"aws_region", "file_system_id", "name"},
+			nil,
+		),
+		ResourcesTotal: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "efs_resources_total"),
+			"The number of EFS file systems listed by the collector.",
+			[]string{"aws_region"},
+			nil,
+		),
+		SizeBytes: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "efs_size_bytes"),
+			"The latest known metered size of the data stored in the file system in bytes.",
+			[]string{"aws_region", "file_system_id", "name"},
+			nil,
+		),
+		logger: logger,
+	}
+}
+
+// Name returns the name of the collector
+func (e *EFSExporter) Name() string {
+	return "efs"
+}
+
+// Enabled returns true if the collector has to be registered
+func (e *EFSExporter) Enabled() bool {
+	return e.enabled
+}
+
+// FiltersTags returns true, the collector applies the tag filter
+func (e *EFSExporter) FiltersTags() bool {
+	return true
+}
+
+// Preflight checks the IAM permissions of the collector with a single call
+func (e *EFSExporter) Preflight() (string, error) {
+	_, err := efs.New(e.sess).DescribeFileSystems(&efs.DescribeFileSystemsInput{MaxItems: aws.Int64(1)})
+	return "elasticfilesystem:DescribeFileSystems", err
+}
+
+// Describe is used by the Prometheus client to return a description of the metrics
+func (e *EFSExporter) Describe(ch chan<- *prometheus.Desc) {
+	ch <- e.LifecycleState
+	ch <- e.NumberOfMountTargets
+	ch <- e.ResourcesTotal
+	ch <- e.SizeBytes
+}
+
+// Collect is used by the Prometheus client to collect and return the metrics values
+func (e *EFSExporter) Collect(ch chan<- prometheus.Metric) {
+	region := sessionRegion(e.sess)
+	svc := efs.New(e.sess)
+	input := &efs.DescribeFileSystemsInput{}
+
+	// Get all file systems.
+	// If a NextMarker is found, do pagination until last page
+	var fileSystems []*efs.FileSystemDescription
+	for {
+		exporterMetrics.IncrementRequests(efs.ServiceName, "DescribeFileSystems")
+		result, err := svc.DescribeFileSystems(input)
+		if err != nil {
+			level.Error(e.logger).Log("msg", "Call to DescribeFileSystems failed", "region", region, "err", err)
+			exporterMetrics.IncrementErrors(efs.ServiceName, "DescribeFileSystems", err)
+			return
+		}
+		for _, fileSystem := range result.FileSystems {
+			tags := map[string]*string{}
+			for _, tag := range fileSystem.Tags {
+				tags[aws.StringValue(tag.Key)] = tag.Value
+			}
+			if !tagFilter.Includes(tags) {
+				continue
+			}
+			fileSystems = append(fileSystems, fileSystem)
+		}
+		input.Marker = result.NextMarker
+		if result.NextMarker == nil {
+			break
+		}
+	}
+	ch <- prometheus.MustNewConstMetric(e.ResourcesTotal, prometheus.GaugeValue, float64(len(fileSystems)), region)
+
+	for _, fileSystem := range fileSystems {
+		// Name is the value of the Name tag, it is not set when the file system has no such tag
+		name := aws.StringValue(fileSystem.Name)
+
+		ch <- prometheus.MustNewConstMetric(e.LifecycleState, prometheus.GaugeValue, 1, region, *fileSystem.FileSystemId, name, aws.StringValue(fileSystem.LifeCycleState))
+		ch <- prometheus.MustNewConstMetric(e.NumberOfMountTargets, prometheus.GaugeValue, float64(aws.Int64Value(fileSystem.NumberOfMountTargets)), region, *fileSystem.FileSystemId, name)
+		// SizeInBytes is briefly missing while the file system is being created
+		if fileSystem.SizeInBytes != nil && fileSystem.SizeInBytes.Value != nil {
+			ch <- prometheus.MustNewConstMetric(e.SizeBytes, prometheus.GaugeValue, float64(*fileSystem.SizeInBytes.Value), region, *fileSystem.FileSystemId, name)
+		}
+	}
+}
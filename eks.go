@@ -0,0 +1,186 @@
+package main
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/eks"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// EKSExporter defines an instance of the EKS Exporter
+type EKSExporter struct {
+	sess                 *session.Session
+	ClusterInfo          *prometheus.Desc
+	NodegroupDesiredSize *prometheus.Desc
+	NodegroupMaxSize     *prometheus.Desc
+	NodegroupMinSize     *prometheus.Desc
+	ResourcesTotal       *prometheus.Desc
+
+	enabled bool
+	logger  log.Logger
+}
+
+func init() {
+	RegisterCollector(func(sess *session.Session, namespace string, logger log.Logger) Collector {
+		return NewEKSExporter(sess, namespace, logger, *eksEnabled)
+	})
+}
+
+// NewEKSExporter creates a new EKSExporter instance
+func NewEKSExporter(sess *session.Session, namespace string, logger log.Logger, enabled bool) *EKSExporter {
+	return &EKSExporter{
+		sess:    sess,
+		enabled: enabled,
+		ClusterInfo: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "eks_cluster_info"),
+			"The Kubernetes version and status of the EKS cluster. The value is always 1.",
+			[]string{"aws_region", "cluster_name", "version", "status"},
+			nil,
+		),
+		NodegroupDesiredSize: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "eks_nodegroup_desired_size"),
+			"The desired number of nodes of the managed node group.",
+			[]string{"aws_region", "cluster_name", "nodegroup_name"},
+			nil,
+		),
+		NodegroupMaxSize: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "eks_nodegroup_max_size"),
+			"The maximum number of nodes of the managed node group.",
+			[]string{"aws_region", "cluster_name", "nodegroup_name"},
+			nil,
+		),
+		NodegroupMinSize: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "eks_nodegroup_min_size"),
+			"The minimum number of nodes of the managed node group.",
+			[]string{"aws_region", "cluster_name", "nodegroup_name"},
+			nil,
+		),
+		ResourcesTotal: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "eks_resources_total"),
+			"The number of EKS clusters listed by the collector.",
+			[]string{"aws_region"},
+			nil,
+		),
+		logger: logger,
+	}
+}
+
+// Name returns the name of the collector
+func (e *EKSExporter) Name() string {
+	return "eks"
+}
+
+// Enabled returns true if the collector has to be registered
+func (e *EKSExporter) Enabled() bool {
+	return e.enabled
+}
+
+// FiltersTags returns true, the collector applies the tag filter
+func (e *EKSExporter) FiltersTags() bool {
+	return true
+}
+
+// Preflight checks the IAM permissions of the collector with a single call
+func (e *EKSExporter) Preflight() (string, error) {
+	_, err := eks.New(e.sess).ListClusters(&eks.ListClustersInput{MaxResults: aws.Int64(1)})
+	return "eks:ListClusters", err
+}
+
+// Describe is used by the Prometheus client to return a description of the metrics
+func (e *EKSExporter) Describe(ch chan<- *prometheus.Desc) {
+	ch <- e.ClusterInfo
+	ch <- e.NodegroupDesiredSize
+	ch <- e.NodegroupMaxSize
+	ch <- e.NodegroupMinSize
+	ch <- e.ResourcesTotal
+}
+
+// Collect is used by the Prometheus client to collect and return the metrics values
+func (e *EKSExporter) Collect(ch chan<- prometheus.Metric) {
+	region := sessionRegion(e.sess)
+	svc := eks.New(e.sess)
+	input := &eks.ListClustersInput{}
+
+	// Get all cluster names.
+	// If a NextToken is found, do pagination until last page
+	var clusterNames []*string
+	for {
+		exporterMetrics.IncrementRequests(eks.ServiceName, "ListClusters")
+		result, err := svc.ListClusters(input)
+		if err != nil {
+			level.Error(e.logger).Log("msg", "Call to ListClusters failed", "region", region, "err", err)
+			exporterMetrics.IncrementErrors(eks.ServiceName, "ListClusters", err)
+			return
+		}
+		clusterNames = append(clusterNames, result.Clusters...)
+		input.NextToken = result.NextToken
+		if result.NextToken == nil {
+			break
+		}
+	}
+
+	// The tags of a cluster are only returned by DescribeCluster, the clusters are counted once filtered
+	total := len(clusterNames)
+	for _, clusterName := range clusterNames {
+		exporterMetrics.IncrementRequests(eks.ServiceName, "DescribeCluster")
+		result, err := svc.DescribeCluster(&eks.DescribeClusterInput{Name: clusterName})
+		if err != nil {
+			level.Error(e.logger).Log("msg", "Call to DescribeCluster failed", "region", region, "cluster", *clusterName, "err", err)
+			exporterMetrics.IncrementErrors(eks.ServiceName, "DescribeCluster", err)
+			continue
+		}
+		if !tagFilter.Includes(result.Cluster.Tags) {
+			total--
+			continue
+		}
+		ch <- prometheus.MustNewConstMetric(e.ClusterInfo, prometheus.GaugeValue, 1, region, *clusterName, aws.StringValue(result.Cluster.Version), aws.StringValue(result.Cluster.Status))
+
+		e.collectNodegroups(ch, region, svc, clusterName)
+	}
+	ch <- prometheus.MustNewConstMetric(e.ResourcesTotal, prometheus.GaugeValue, float64(total), region)
+}
+
+// collectNodegroups collects the scaling configuration of all the managed node groups of the cluster
+func (e *EKSExporter) collectNodegroups(ch chan<- prometheus.Metric, region string, svc *eks.EKS, clusterName *string) {
+	input := &eks.ListNodegroupsInput{ClusterName: clusterName}
+
+	// Get all node group names.
+	// If a NextToken is found, do pagination until last page
+	var nodegroupNames []*string
+	for {
+		exporterMetrics.IncrementRequests(eks.ServiceName, "ListNodegroups")
+		result, err := svc.ListNodegroups(input)
+		if err != nil {
+			level.Error(e.logger).Log("msg", "Call to ListNodegroups failed", "region", region, "cluster", *clusterName, "err", err)
+			exporterMetrics.IncrementErrors(eks.ServiceName, "ListNodegroups", err)
+			return
+		}
+		nodegroupNames = append(nodegroupNames, result.Nodegroups...)
+		input.NextToken = result.NextToken
+		if result.NextToken == nil {
+			break
+		}
+	}
+
+	for _, nodegroupName := range nodegroupNames {
+		exporterMetrics.IncrementRequests(eks.ServiceName, "DescribeNodegroup")
+		result, err := svc.DescribeNodegroup(&eks.DescribeNodegroupInput{
+			ClusterName:   clusterName,
+			NodegroupName: nodegroupName,
+		})
+		if err != nil {
+			level.Error(e.logger).Log("msg", "Call to DescribeNodegroup failed", "region", region, "cluster", *clusterName, "nodegroup", *nodegroupName, "err", err)
+			exporterMetrics.IncrementErrors(eks.ServiceName, "DescribeNodegroup", err)
+			continue
+		}
+		scaling := result.Nodegroup.ScalingConfig
+		if scaling == nil {
+			continue
+		}
+		ch <- prometheus.MustNewConstMetric(e.NodegroupDesiredSize, prometheus.GaugeValue, float64(aws.Int64Value(scaling.DesiredSize)), region, *clusterName, *nodegroupName)
+		ch <- prometheus.MustNewConstMetric(e.NodegroupMinSize, prometheus.GaugeValue, float64(aws.Int64Value(scaling.MinSize)), region, *clusterName, *nodegroupName)
+		ch <- prometheus.MustNewConstMetric(e.NodegroupMaxSize, prometheus.GaugeValue, float64(aws.Int64Value(scaling.MaxSize)), region, *clusterName, *nodegroupName)
+	}
+}
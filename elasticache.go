@@ -0,0 +1,141 @@
+package main
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/elasticache"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// ElastiCacheExporter defines an instance of the ElastiCache Exporter
+type ElastiCacheExporter struct {
+	sess                              *session.Session
+	ReplicationGroupAutomaticFailover *prometheus.Desc
+	ReplicationGroupNodeCount         *prometheus.Desc
+	ReplicationGroupStatus            *prometheus.Desc
+	ResourcesTotal                    *prometheus.Desc
+
+	enabled bool
+	logger  log.Logger
+}
+
+func init() {
+	RegisterCollector(func(sess *session.Session, namespace string, logger log.Logger) Collector {
+		return NewElastiCacheExporter(sess, namespace, logger, *elastiCacheEnabled)
+	})
+}
+
+// NewElastiCacheExporter creates a new ElastiCacheExporter instance
+func NewElastiCacheExporter(sess *session.Session, namespace string, logger log.Logger, enabled bool) *ElastiCacheExporter {
+	return &ElastiCacheExporter{
+		sess:    sess,
+		enabled: enabled,
+		ReplicationGroupAutomaticFailover: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "elasticache_replication_group_automatic_failover"),
+			"The automatic failover status of the replication group. The value is always 1.",
+			[]string{"aws_region", "replication_group_id", "status"},
+			nil,
+		),
+		ReplicationGroupNodeCount: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "elasticache_replication_group_node_count"),
+			"The number of cache nodes of the replication group.",
+			[]string{"aws_region", "replication_group_id"},
+			nil,
+		),
+		ReplicationGroupStatus: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "elasticache_replication_group_status"),
+			"The status of the replication group. The value is always 1.",
+			[]string{"aws_region", "replication_group_id", "status"},
+			nil,
+		),
+		ResourcesTotal: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "elasticache_resources_total"),
+			"The number of ElastiCache replication groups listed by the collector.",
+			[]string{"aws_region"},
+			nil,
+		),
+		logger: logger,
+	}
+}
+
+// Name returns the name of the collector
+func (e *ElastiCacheExporter) Name() string {
+	return "elasticache"
+}
+
+// Enabled returns true if the collector has to be registered
+func (e *ElastiCacheExporter) Enabled() bool {
+	return e.enabled
+}
+
+// FiltersTags returns true, the collector applies the tag filter
+func (e *ElastiCacheExporter) FiltersTags() bool {
+	return true
+}
+
+// Preflight checks the IAM permissions of the collector with a single call
+func (e *ElastiCacheExporter) Preflight() (string, error) {
+	_, err := elasticache.New(e.sess).DescribeReplicationGroups(&elasticache.DescribeReplicationGroupsInput{MaxRecords: aws.Int64(20)})
+	return "elasticache:DescribeReplicationGroups", err
+}
+
+// Describe is used by the Prometheus client to return a description of the metrics
+func (e *ElastiCacheExporter) Describe(ch chan<- *prometheus.Desc) {
+	ch <- e.ReplicationGroupAutomaticFailover
+	ch <- e.ReplicationGroupNodeCount
+	ch <- e.ReplicationGroupStatus
+	ch <- e.ResourcesTotal
+}
+
+// Collect is used by the Prometheus client to collect and return the metrics values
+func (e *ElastiCacheExporter) Collect(ch chan<- prometheus.Metric) {
+	region := sessionRegion(e.sess)
+	svc := elasticache.New(e.sess)
+
+	// The Resource Groups Tagging API filters the replication groups on their tags, the groups are listed without their ARN
+	var taggedGroups map[string]bool
+	if tagFilter != nil {
+		ids, err := tagFilter.ResourceIDs(e.sess, "elasticache:replicationgroup")
+		if err != nil {
+			level.Error(e.logger).Log("msg", "Call to GetResources failed", "region", region, "err", err)
+			return
+		}
+		taggedGroups = ids
+	}
+
+	input := &elasticache.DescribeReplicationGroupsInput{}
+
+	// Get all replication groups.
+	// If a Marker is found, do pagination until last page
+	var groups []*elasticache.ReplicationGroup
+	for {
+		exporterMetrics.IncrementRequests(elasticache.ServiceName, "DescribeReplicationGroups")
+		result, err := svc.DescribeReplicationGroups(input)
+		if err != nil {
+			level.Error(e.logger).Log("msg", "Call to DescribeReplicationGroups failed", "region", region, "err", err)
+			exporterMetrics.IncrementErrors(elasticache.ServiceName, "DescribeReplicationGroups", err)
+			return
+		}
+		for _, group := range result.ReplicationGroups {
+			if taggedGroups != nil && !taggedGroups[aws.StringValue(group.ReplicationGroupId)] {
+				continue
+			}
+			groups = append(groups, group)
+		}
+		input.Marker = result.Marker
+		if result.Marker == nil {
+			break
+		}
+	}
+	ch <- prometheus.MustNewConstMetric(e.ResourcesTotal, prometheus.GaugeValue, float64(len(groups)), region)
+
+	for _, group := range groups {
+		groupID := aws.StringValue(group.ReplicationGroupId)
+		// Every member cluster of a Redis replication group is a single node, in cluster mode as well
+		ch <- prometheus.MustNewConstMetric(e.ReplicationGroupNodeCount, prometheus.GaugeValue, float64(len(group.MemberClusters)), region, groupID)
+		ch <- prometheus.MustNewConstMetric(e.ReplicationGroupStatus, prometheus.GaugeValue, 1, region, groupID, aws.StringValue(group.Status))
+		ch <- prometheus.MustNewConstMetric(e.ReplicationGroupAutomaticFailover, prometheus.GaugeValue, 1, region, groupID, aws.StringValue(group.AutomaticFailover))
+	}
+}
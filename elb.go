@@ -0,0 +1,181 @@
+package main
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// ELBExporter defines an instance of the Elastic Load Balancing v2 Exporter
+type ELBExporter struct {
+	sess                 *session.Session
+	ResourcesTotal       *prometheus.Desc
+	TargetHealthyCount   *prometheus.Desc
+	TargetUnhealthyCount *prometheus.Desc
+
+	enabled bool
+	logger  log.Logger
+}
+
+func init() {
+	RegisterCollector(func(sess *session.Session, namespace string, logger log.Logger) Collector {
+		return NewELBExporter(sess, namespace, logger, *elbEnabled)
+	})
+}
+
+// NewELBExporter creates a new ELBExporter instance
+func NewELBExporter(sess *session.Session, namespace string, logger log.Logger, enabled bool) *ELBExporter {
+	return &ELBExporter{
+		sess:    sess,
+		enabled: enabled,
+		ResourcesTotal: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "elb_resources_total"),
+			"The number of load balancers listed by the collector.",
+			[]string{"aws_region"},
+			nil,
+		),
+		TargetHealthyCount: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "elb_target_healthy_count"),
+			"The number of healthy targets in the target group.",
+			[]string{"aws_region", "load_balancer_arn", "target_group_arn"},
+			nil,
+		),
+		TargetUnhealthyCount: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "elb_target_unhealthy_count"),
+			"The number of unhealthy targets in the target group.",
+			[]string{"aws_region", "load_balancer_arn", "target_group_arn"},
+			nil,
+		),
+		logger: logger,
+	}
+}
+
+// Name returns the name of the collector
+func (e *ELBExporter) Name() string {
+	return "elb"
+}
+
+// Enabled returns true if the collector has to be registered
+func (e *ELBExporter) Enabled() bool {
+	return e.enabled
+}
+
+// FiltersTags returns true, the collector applies the tag filter
+func (e *ELBExporter) FiltersTags() bool {
+	return true
+}
+
+// Preflight checks the IAM permissions of the collector with a single call
+func (e *ELBExporter) Preflight() (string, error) {
+	_, err := elbv2.New(e.sess).DescribeLoadBalancers(&elbv2.DescribeLoadBalancersInput{PageSize: aws.Int64(1)})
+	return "elasticloadbalancing:DescribeLoadBalancers", err
+}
+
+// Describe is used by the Prometheus client to return a description of the metrics
+func (e *ELBExporter) Describe(ch chan<- *prometheus.Desc) {
+	ch <- e.ResourcesTotal
+	ch <- e.TargetHealthyCount
+	ch <- e.TargetUnhealthyCount
+}
+
+// Collect is used by the Prometheus client to collect and return the metrics values
+func (e *ELBExporter) Collect(ch chan<- prometheus.Metric) {
+	region := sessionRegion(e.sess)
+	svc := elbv2.New(e.sess)
+
+	// The Resource Groups Tagging API filters the load balancers on their tags
+	var taggedLoadBalancers map[string]bool
+	if tagFilter != nil {
+		arns, err := tagFilter.ResourceARNs(e.sess, "elasticloadbalancing:loadbalancer")
+		if err != nil {
+			level.Error(e.logger).Log("msg", "Call to GetResources failed", "region", region, "err", err)
+			return
+		}
+		taggedLoadBalancers = arns
+	}
+
+	input := &elbv2.DescribeLoadBalancersInput{}
+
+	// Get all load balancers.
+	// If a NextMarker is found, do pagination until last page
+	var loadBalancers []*elbv2.LoadBalancer
+	for {
+		exporterMetrics.IncrementRequests(elbv2.ServiceName, "DescribeLoadBalancers")
+		result, err := svc.DescribeLoadBalancers(input)
+		if err != nil {
+			level.Error(e.logger).Log("msg", "Call to DescribeLoadBalancers failed", "region", region, "err", err)
+			exporterMetrics.IncrementErrors(elbv2.ServiceName, "DescribeLoadBalancers", err)
+			return
+		}
+		for _, loadBalancer := range result.LoadBalancers {
+			if taggedLoadBalancers != nil && !taggedLoadBalancers[aws.StringValue(loadBalancer.LoadBalancerArn)] {
+				continue
+			}
+			loadBalancers = append(loadBalancers, loadBalancer)
+		}
+		input.Marker = result.NextMarker
+		if result.NextMarker == nil {
+			break
+		}
+	}
+	ch <- prometheus.MustNewConstMetric(e.ResourcesTotal, prometheus.GaugeValue, float64(len(loadBalancers)), region)
+
+	for _, loadBalancer := range loadBalancers {
+		e.collectTargetGroups(ch, region, svc, loadBalancer)
+	}
+}
+
+// collectTargetGroups collects the target health of all the target groups of the load balancer
+func (e *ELBExporter) collectTargetGroups(ch chan<- prometheus.Metric, region string, svc *elbv2.ELBV2, loadBalancer *elbv2.LoadBalancer) {
+	input := &elbv2.DescribeTargetGroupsInput{
+		LoadBalancerArn: loadBalancer.LoadBalancerArn,
+	}
+
+	// Get all target groups of the load balancer.
+	// If a NextMarker is found, do pagination until last page
+	var targetGroups []*elbv2.TargetGroup
+	for {
+		exporterMetrics.IncrementRequests(elbv2.ServiceName, "DescribeTargetGroups")
+		result, err := svc.DescribeTargetGroups(input)
+		if err != nil {
+			level.Error(e.logger).Log("msg", "Call to DescribeTargetGroups failed", "region", region, "load_balancer", *loadBalancer.LoadBalancerArn, "err", err)
+			exporterMetrics.IncrementErrors(elbv2.ServiceName, "DescribeTargetGroups", err)
+			return
+		}
+		targetGroups = append(targetGroups, result.TargetGroups...)
+		input.Marker = result.NextMarker
+		if result.NextMarker == nil {
+			break
+		}
+	}
+
+	for _, targetGroup := range targetGroups {
+		exporterMetrics.IncrementRequests(elbv2.ServiceName, "DescribeTargetHealth")
+		result, err := svc.DescribeTargetHealth(&elbv2.DescribeTargetHealthInput{
+			TargetGroupArn: targetGroup.TargetGroupArn,
+		})
+		if err != nil {
+			level.Error(e.logger).Log("msg", "Call to DescribeTargetHealth failed", "region", region, "target_group", *targetGroup.TargetGroupArn, "err", err)
+			exporterMetrics.IncrementErrors(elbv2.ServiceName, "DescribeTargetHealth", err)
+			continue
+		}
+
+		var healthy, unhealthy int
+		for _, description := range result.TargetHealthDescriptions {
+			if description.TargetHealth == nil {
+				continue
+			}
+			switch aws.StringValue(description.TargetHealth.State) {
+			case elbv2.TargetHealthStateEnumHealthy:
+				healthy++
+			case elbv2.TargetHealthStateEnumUnhealthy:
+				unhealthy++
+			}
+		}
+		ch <- prometheus.MustNewConstMetric(e.TargetHealthyCount, prometheus.GaugeValue, float64(healthy), region, *loadBalancer.LoadBalancerArn, *targetGroup.TargetGroupArn)
+		ch <- prometheus.MustNewConstMetric(e.TargetUnhealthyCount, prometheus.GaugeValue, float64(unhealthy), region, *loadBalancer.LoadBalancerArn, *targetGroup.TargetGroupArn)
+	}
+}
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/eventbridge"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// The states of an EventBridge rule, every state is exported with 1 for the current one
+var eventBridgeRuleStates = []string{
+	eventbridge.RuleStateEnabled,
+	eventbridge.RuleStateDisabled,
+}
+
+// EventBridgeExporter defines an instance of the EventBridge Exporter
+type EventBridgeExporter struct {
+	sess           *session.Session
+	ResourcesTotal *prometheus.Desc
+	RuleInfo       *prometheus.Desc
+	RuleState      *prometheus.Desc
+
+	enabled bool
+	logger  log.Logger
+}
+
+func init() {
+	RegisterCollector(func(sess *session.Session, namespace string, logger log.Logger) Collector {
+		return NewEventBridgeExporter(sess, namespace, logger, *eventBridgeEnabled)
+	})
+}
+
+// NewEventBridgeExporter creates a new EventBridgeExporter instance
+func NewEventBridgeExporter(sess *session.Session, namespace string, logger log.Logger, enabled bool) *EventBridgeExporter {
+	return &EventBridgeExporter{
+		sess:    sess,
+		enabled: enabled,
+		ResourcesTotal: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "eventbridge_resources_total"),
+			"The number of EventBridge rules listed by the collector.",
+			[]string{"aws_region"},
+			nil,
+		),
+		RuleInfo: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "eventbridge_rule_info"),
+			"The event bus of the rule. The value is always 1.",
+			[]string{"aws_region", "rule_name", "event_bus_name"},
+			nil,
+		),
+		RuleState: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "eventbridge_rule_state"),
+			"Indicates if the rule is in the state, ENABLED or DISABLED.",
+			[]string{"aws_region", "rule_name", "event_bus_name", "state"},
+			nil,
+		),
+		logger: logger,
+	}
+}
+
+// Name returns the name of the collector
+func (e *EventBridgeExporter) Name() string {
+	return "eventbridge"
+}
+
+// Enabled returns true if the collector has to be registered
+func (e *EventBridgeExporter) Enabled() bool {
+	return e.enabled
+}
+
+// FiltersTags returns true, the collector applies the tag filter
+func (e *EventBridgeExporter) FiltersTags() bool {
+	return true
+}
+
+// Preflight checks the IAM permissions of the collector with a single call
+func (e *EventBridgeExporter) Preflight() (string, error) {
+	_, err := eventbridge.New(e.sess).ListEventBuses(&eventbridge.ListEventBusesInput{Limit: aws.Int64(1)})
+	return "events:ListEventBuses", err
+}
+
+// Describe is used by the Prometheus client to return a description of the metrics
+func (e *EventBridgeExporter) Describe(ch chan<- *prometheus.Desc) {
+	ch <- e.ResourcesTotal
+	ch <- e.RuleInfo
+	ch <- e.RuleState
+}
+
+// Collect is used by the Prometheus client to collect and return the metrics values
+func (e *EventBridgeExporter) Collect(ch chan<- prometheus.Metric) {
+	region := sessionRegion(e.sess)
+	svc := eventbridge.New(e.sess)
+
+	// The Resource Groups Tagging API filters the rules on their tags
+	var taggedRules map[string]bool
+	if tagFilter != nil {
+		arns, err := tagFilter.ResourceARNs(e.sess, "events:rule")
+		if err != nil {
+			level.Error(e.logger).Log("msg", "Call to GetResources failed", "region", region, "err", err)
+			return
+		}
+		taggedRules = arns
+	}
+
+	input := &eventbridge.ListEventBusesInput{}
+
+	// Get all event buses.
+	// If a NextToken is found, do pagination until last page
+	var buses []*eventbridge.EventBus
+	for {
+		exporterMetrics.IncrementRequests(eventbridge.ServiceName, "ListEventBuses")
+		result, err := svc.ListEventBuses(input)
+		if err != nil {
+			level.Error(e.logger).Log("msg", "Call to ListEventBuses failed", "region", region, "err", err)
+			exporterMetrics.IncrementErrors(eventbridge.ServiceName, "ListEventBuses", err)
+			return
+		}
+		buses = append(buses, result.EventBuses...)
+		input.NextToken = result.NextToken
+		if result.NextToken == nil {
+			break
+		}
+	}
+
+	var rules []*eventbridge.Rule
+	for _, bus := range buses {
+		busRules, err := e.listRules(region, svc, bus.Name)
+		if err != nil {
+			continue
+		}
+		for _, rule := range busRules {
+			if taggedRules != nil && !taggedRules[aws.StringValue(rule.Arn)] {
+				continue
+			}
+			rules = append(rules, rule)
+		}
+	}
+	ch <- prometheus.MustNewConstMetric(e.ResourcesTotal, prometheus.GaugeValue, float64(len(rules)), region)
+
+	for _, rule := range rules {
+		ruleName := aws.StringValue(rule.Name)
+		busName := aws.StringValue(rule.EventBusName)
+		ch <- prometheus.MustNewConstMetric(e.RuleInfo, prometheus.GaugeValue, 1, region, ruleName, busName)
+		for _, state := range eventBridgeRuleStates {
+			if state == aws.StringValue(rule.State) {
+				ch <- prometheus.MustNewConstMetric(e.RuleState, prometheus.GaugeValue, 1, region, ruleName, busName, state)
+			} else {
+				ch <- prometheus.MustNewConstMetric(e.RuleState, prometheus.GaugeValue, 0, region, ruleName, busName, state)
+			}
+		}
+	}
+}
+
+// listRules returns all the rules of the event bus
+func (e *EventBridgeExporter) listRules(region string, svc *eventbridge.EventBridge, busName *string) ([]*eventbridge.Rule, error) {
+	input := &eventbridge.ListRulesInput{EventBusName: busName}
+
+	// Get all rules of the event bus.
+	// If a NextToken is found, do pagination until last page
+	var rules []*eventbridge.Rule
+	for {
+		exporterMetrics.IncrementRequests(eventbridge.ServiceName, "ListRules")
+		result, err := svc.ListRules(input)
+		if err != nil {
+			level.Error(e.logger).Log("msg", "Call to ListRules failed", "region", region, "event_bus", aws.StringValue(busName), "err", err)
+			exporterMetrics.IncrementErrors(eventbridge.ServiceName, "ListRules", err)
+			return nil, err
+		}
+		rules = append(rules, result.Rules...)
+		input.NextToken = result.NextToken
+		if result.NextToken == nil {
+			break
+		}
+	}
+	return rules, nil
+}
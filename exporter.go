@@ -1,8 +1,13 @@
 package main
 
 import (
-	"sync"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/prometheus/client_golang/prometheus"
 )
@@ -11,57 +16,198 @@ import (
 type ExporterMetrics struct {
 	sess *session.Session
 
-	APIRequestsCount float64
-	APIErrorsCount   float64
+	APIRequests          *prometheus.CounterVec
+	APIErrors            *prometheus.CounterVec
+	CollectorDuration    *prometheus.HistogramVec
+	CollectorEnabled     *prometheus.GaugeVec
+	InflightRequests     prometheus.Gauge
+	LastScrapeTimestamp  *prometheus.GaugeVec
+	LastSuccessTimestamp *prometheus.GaugeVec
+	RegionUnavailable    *prometheus.GaugeVec
+}
+
+// The error codes returned by the AWS APIs when the region is not enabled for the account
+var regionUnavailableErrorCodes = map[string]bool{
+	"OptInRequired": true,
+	"AuthFailure":   true,
+}
+
+// IsRegionUnavailable returns true if the error means that the region is not enabled for the account
+func IsRegionUnavailable(err error) bool {
+	if aerr, ok := err.(awserr.Error); ok {
+		return regionUnavailableErrorCodes[aerr.Code()]
+	}
+	return false
+}
 
-	APIRequests *prometheus.Desc
-	APIErrors   *prometheus.Desc
+// The error codes returned by the AWS APIs when the caller is missing an IAM permission
+var accessDeniedErrorCodes = map[string]bool{
+	"AccessDenied":          true,
+	"AccessDeniedException": true,
+	"AuthorizationError":    true,
+	"UnauthorizedOperation": true,
+}
 
-	mutex *sync.Mutex
+// IsAccessDenied returns true if the error means that the caller is missing an IAM permission
+func IsAccessDenied(err error) bool {
+	if aerr, ok := err.(awserr.Error); ok {
+		return accessDeniedErrorCodes[aerr.Code()]
+	}
+	return false
 }
 
-// NewExporterMetrics creates a new exporter metrics instance
-func NewExporterMetrics(sess *session.Session) *ExporterMetrics {
+// ParseHistogramBuckets parses a comma separated list of histogram bucket upper bounds, in seconds
+func ParseHistogramBuckets(buckets string) ([]float64, error) {
+	var parsed []float64
+	for _, bucket := range strings.Split(buckets, ",") {
+		value, err := strconv.ParseFloat(strings.TrimSpace(bucket), 64)
+		if err != nil || value <= 0 {
+			return nil, fmt.Errorf("invalid histogram bucket %q, expected a positive number of seconds", bucket)
+		}
+		parsed = append(parsed, value)
+	}
+	sort.Float64s(parsed)
+	return parsed, nil
+}
+
+// NewExporterMetrics creates a new exporter metrics instance, the collection durations are observed in durationBuckets
+func NewExporterMetrics(sess *session.Session, namespace string, durationBuckets []float64) *ExporterMetrics {
 	return &ExporterMetrics{
 		sess: sess,
-		APIRequests: prometheus.NewDesc(
-			prometheus.BuildFQName(namespace, "", "apirequests"),
-			"API requests made by the exporter.",
-			[]string{},
-			nil,
+		APIRequests: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Name:      "api_requests_total",
+				Help:      "API requests made by the exporter.",
+			},
+			[]string{"service", "operation"},
+		),
+		APIErrors: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Name:      "api_errors_total",
+				Help:      "API errors encountered by the exporter.",
+			},
+			[]string{"service", "operation", "error_code"},
+		),
+		CollectorDuration: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Namespace: namespace,
+				Name:      "collector_duration_seconds",
+				Help:      "Duration of the fetches of the metrics of the collector from AWS, in seconds.",
+				Buckets:   durationBuckets,
+			},
+			[]string{"aws_account_id", "collector"},
+		),
+		CollectorEnabled: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Name:      "collector_enabled",
+				Help:      "Indicates if the collector is registered, collectors failing the preflight check can be disabled.",
+			},
+			[]string{"aws_account_id", "collector"},
+		),
+		InflightRequests: prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Name:      "inflight_requests",
+				Help:      "API requests currently in flight.",
+			},
 		),
-		APIErrors: prometheus.NewDesc(
-			prometheus.BuildFQName(namespace, "", "apierrors"),
-			"API errors encountered by the exporter.",
-			[]string{},
-			nil,
+		LastScrapeTimestamp: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Name:      "last_scrape_timestamp",
+				Help:      "Unix timestamp of the last time the collector fetched its metrics from AWS.",
+			},
+			[]string{"aws_account_id", "collector"},
+		),
+		LastSuccessTimestamp: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Name:      "last_success_timestamp",
+				Help:      "Unix timestamp of the last time the collector fetched its metrics from AWS without any failed API call.",
+			},
+			[]string{"aws_account_id", "collector"},
+		),
+		RegionUnavailable: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Name:      "region_unavailable",
+				Help:      "Indicates if the region is not enabled for the account and was skipped.",
+			},
+			[]string{"aws_account_id", "region"},
 		),
-		mutex: &sync.Mutex{},
 	}
 }
 
 // Describe is used by the Prometheus client to return a description of the metrics
 func (e *ExporterMetrics) Describe(ch chan<- *prometheus.Desc) {
-	ch <- e.APIRequests
-	ch <- e.APIErrors
+	e.APIRequests.Describe(ch)
+	e.APIErrors.Describe(ch)
+	e.CollectorDuration.Describe(ch)
+	e.CollectorEnabled.Describe(ch)
+	e.InflightRequests.Describe(ch)
+	e.LastScrapeTimestamp.Describe(ch)
+	e.LastSuccessTimestamp.Describe(ch)
+	e.RegionUnavailable.Describe(ch)
 }
 
 // Collect is used by the Prometheus client to collect and return the metrics values
 func (e *ExporterMetrics) Collect(ch chan<- prometheus.Metric) {
-	ch <- prometheus.MustNewConstMetric(e.APIRequests, prometheus.CounterValue, e.APIRequestsCount)
-	ch <- prometheus.MustNewConstMetric(e.APIErrors, prometheus.CounterValue, e.APIErrorsCount)
+	e.APIRequests.Collect(ch)
+	e.APIErrors.Collect(ch)
+	e.CollectorDuration.Collect(ch)
+	e.CollectorEnabled.Collect(ch)
+	e.InflightRequests.Collect(ch)
+	e.LastScrapeTimestamp.Collect(ch)
+	e.LastSuccessTimestamp.Collect(ch)
+	e.RegionUnavailable.Collect(ch)
+}
+
+// IncrementRequests increments the API requests counter of the service operation
+func (e *ExporterMetrics) IncrementRequests(service, operation string) {
+	e.APIRequests.WithLabelValues(service, operation).Inc()
 }
 
-// IncrementRequests increments the API requests counter
-func (e *ExporterMetrics) IncrementRequests() {
-	e.mutex.Lock()
-	e.APIRequestsCount++
-	e.mutex.Unlock()
+// IncrementErrors increments the API errors counter of the service operation, labeled with the AWS error code
+func (e *ExporterMetrics) IncrementErrors(service, operation string, err error) {
+	code := "unknown"
+	if aerr, ok := err.(awserr.Error); ok {
+		code = aerr.Code()
+	}
+	e.APIErrors.WithLabelValues(service, operation, code).Inc()
+}
+
+// MarkScraped records that the collector of the account just fetched its metrics from AWS
+func (e *ExporterMetrics) MarkScraped(account, collector string) {
+	e.LastScrapeTimestamp.WithLabelValues(account, collector).SetToCurrentTime()
 }
 
-// IncrementErrors increments the API requests error counter
-func (e *ExporterMetrics) IncrementErrors() {
-	e.mutex.Lock()
-	e.APIErrorsCount++
-	e.mutex.Unlock()
+// ObserveDuration records how long the collector of the account took to fetch its metrics from AWS
+func (e *ExporterMetrics) ObserveDuration(account, collector string, duration time.Duration) {
+	e.CollectorDuration.WithLabelValues(account, collector).Observe(duration.Seconds())
+}
+
+// MarkSucceeded records that the collector of the account just fetched its metrics from AWS without any failed API call
+func (e *ExporterMetrics) MarkSucceeded(account, collector string) {
+	e.LastSuccessTimestamp.WithLabelValues(account, collector).SetToCurrentTime()
+}
+
+// SetCollectorEnabled records whether the collector of the account is registered
+func (e *ExporterMetrics) SetCollectorEnabled(account, collector string, enabled bool) {
+	if enabled {
+		e.CollectorEnabled.WithLabelValues(account, collector).Set(1)
+	} else {
+		e.CollectorEnabled.WithLabelValues(account, collector).Set(0)
+	}
+}
+
+// SetRegionUnavailable records whether the region is enabled for the account
+func (e *ExporterMetrics) SetRegionUnavailable(account, region string, unavailable bool) {
+	if unavailable {
+		e.RegionUnavailable.WithLabelValues(account, region).Set(1)
+	} else {
+		e.RegionUnavailable.WithLabelValues(account, region).Set(0)
+	}
 }
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+)
+
+func TestIsRegionUnavailable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "no error", err: nil, want: false},
+		{name: "opt-in required", err: awserr.New("OptInRequired", "region not enabled", nil), want: true},
+		{name: "auth failure", err: awserr.New("AuthFailure", "region not enabled", nil), want: true},
+		{name: "throttling", err: awserr.New("Throttling", "rate exceeded", nil), want: false},
+		{name: "access denied", err: awserr.New("AccessDenied", "missing permission", nil), want: false},
+		{name: "not an AWS error", err: errors.New("OptInRequired"), want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsRegionUnavailable(tt.err); got != tt.want {
+				t.Errorf("IsRegionUnavailable() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
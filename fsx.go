@@ -0,0 +1,137 @@
+package main
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/fsx"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// FSxExporter defines an instance of the FSx Exporter
+type FSxExporter struct {
+	sess                 *session.Session
+	LifecycleState       *prometheus.Desc
+	ResourcesTotal       *prometheus.Desc
+	StorageCapacityBytes *prometheus.Desc
+	ThroughputCapacity   *prometheus.Desc
+
+	enabled bool
+	logger  log.Logger
+}
+
+func init() {
+	RegisterCollector(func(sess *session.Session, namespace string, logger log.Logger) Collector {
+		return NewFSxExporter(sess, namespace, logger, *fsxEnabled)
+	})
+}
+
+// NewFSxExporter creates a new FSxExporter instance
+func NewFSxExporter(sess *session.Session, namespace string, logger log.Logger, enabled bool) *FSxExporter {
+	return &FSxExporter{
+		sess:    sess,
+		enabled: enabled,
+		LifecycleState: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "fsx_lifecycle_state"),
+			"The lifecycle state and type of the file system. The value is always 1.",
+			[]string{"aws_region", "file_system_id", "state", "file_system_type"},
+			nil,
+		),
+		ResourcesTotal: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "fsx_resources_total"),
+			"The number of FSx file systems listed by the collector.",
+			[]string{"aws_region"},
+			nil,
+		),
+		StorageCapacityBytes: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "fsx_storage_capacity_bytes"),
+			"The storage capacity of the file system in bytes.",
+			[]string{"aws_region", "file_system_id"},
+			nil,
+		),
+		ThroughputCapacity: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "fsx_throughput_capacity"),
+			"The throughput capacity of the Windows file system in MB/s.",
+			[]string{"aws_region", "file_system_id"},
+			nil,
+		),
+		logger: logger,
+	}
+}
+
+// Name returns the name of the collector
+func (e *FSxExporter) Name() string {
+	return "fsx"
+}
+
+// Enabled returns true if the collector has to be registered
+func (e *FSxExporter) Enabled() bool {
+	return e.enabled
+}
+
+// FiltersTags returns true, the collector applies the tag filter
+func (e *FSxExporter) FiltersTags() bool {
+	return true
+}
+
+// Preflight checks the IAM permissions of the collector with a single call
+func (e *FSxExporter) Preflight() (string, error) {
+	_, err := fsx.New(e.sess).DescribeFileSystems(&fsx.DescribeFileSystemsInput{MaxResults: aws.Int64(1)})
+	return "fsx:DescribeFileSystems", err
+}
+
+// Describe is used by the Prometheus client to return a description of the metrics
+func (e *FSxExporter) Describe(ch chan<- *prometheus.Desc) {
+	ch <- e.LifecycleState
+	ch <- e.ResourcesTotal
+	ch <- e.StorageCapacityBytes
+	ch <- e.ThroughputCapacity
+}
+
+// Collect is used by the Prometheus client to collect and return the metrics values
+func (e *FSxExporter) Collect(ch chan<- prometheus.Metric) {
+	region := sessionRegion(e.sess)
+	svc := fsx.New(e.sess)
+	input := &fsx.DescribeFileSystemsInput{}
+
+	// Get all file systems.
+	// If a NextToken is found, do pagination until last page
+	var fileSystems []*fsx.FileSystem
+	for {
+		exporterMetrics.IncrementRequests(fsx.ServiceName, "DescribeFileSystems")
+		result, err := svc.DescribeFileSystems(input)
+		if err != nil {
+			level.Error(e.logger).Log("msg", "Call to DescribeFileSystems failed", "region", region, "err", err)
+			exporterMetrics.IncrementErrors(fsx.ServiceName, "DescribeFileSystems", err)
+			return
+		}
+		for _, fileSystem := range result.FileSystems {
+			tags := map[string]*string{}
+			for _, tag := range fileSystem.Tags {
+				tags[aws.StringValue(tag.Key)] = tag.Value
+			}
+			if !tagFilter.Includes(tags) {
+				continue
+			}
+			fileSystems = append(fileSystems, fileSystem)
+		}
+		input.NextToken = result.NextToken
+		if result.NextToken == nil {
+			break
+		}
+	}
+	ch <- prometheus.MustNewConstMetric(e.ResourcesTotal, prometheus.GaugeValue, float64(len(fileSystems)), region)
+
+	for _, fileSystem := range fileSystems {
+		ch <- prometheus.MustNewConstMetric(e.LifecycleState, prometheus.GaugeValue, 1, region, *fileSystem.FileSystemId, aws.StringValue(fileSystem.Lifecycle), aws.StringValue(fileSystem.FileSystemType))
+		// StorageCapacity is in GiB
+		if fileSystem.StorageCapacity != nil {
+			ch <- prometheus.MustNewConstMetric(e.StorageCapacityBytes, prometheus.GaugeValue, float64(*fileSystem.StorageCapacity*1024*1024*1024), region, *fileSystem.FileSystemId)
+		}
+		// Only the Windows file systems have a provisioned throughput capacity
+		if fileSystem.WindowsConfiguration != nil && fileSystem.WindowsConfiguration.ThroughputCapacity != nil {
+			ch <- prometheus.MustNewConstMetric(e.ThroughputCapacity, prometheus.GaugeValue, float64(*fileSystem.WindowsConfiguration.ThroughputCapacity), region, *fileSystem.FileSystemId)
+		}
+	}
+}
@@ -0,0 +1,193 @@
+package main
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/glue"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// GlueExporter defines an instance of the Glue Exporter
+type GlueExporter struct {
+	sess                 *session.Session
+	CrawlerLastRunStatus *prometheus.Desc
+	CrawlerState         *prometheus.Desc
+	JobInfo              *prometheus.Desc
+	ResourcesTotal       *prometheus.Desc
+
+	enabled bool
+	logger  log.Logger
+}
+
+func init() {
+	RegisterCollector(func(sess *session.Session, namespace string, logger log.Logger) Collector {
+		return NewGlueExporter(sess, namespace, logger, *glueEnabled)
+	})
+}
+
+// NewGlueExporter creates a new GlueExporter instance
+func NewGlueExporter(sess *session.Session, namespace string, logger log.Logger, enabled bool) *GlueExporter {
+	return &GlueExporter{
+		sess:    sess,
+		enabled: enabled,
+		CrawlerLastRunStatus: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "glue_crawler_last_run_status"),
+			"The status of the last run of the crawler. The value is always 1.",
+			[]string{"aws_region", "crawler_name", "status"},
+			nil,
+		),
+		CrawlerState: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "glue_crawler_state"),
+			"The state of the crawler. The value is always 1.",
+			[]string{"aws_region", "crawler_name", "state"},
+			nil,
+		),
+		JobInfo: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "glue_job_info"),
+			"Information about the job. The value is always 1.",
+			[]string{"aws_region", "job_name", "command_name"},
+			nil,
+		),
+		ResourcesTotal: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "glue_resources_total"),
+			"The number of Glue jobs listed by the collector.",
+			[]string{"aws_region"},
+			nil,
+		),
+		logger: logger,
+	}
+}
+
+// Name returns the name of the collector
+func (e *GlueExporter) Name() string {
+	return "glue"
+}
+
+// Enabled returns true if the collector has to be registered
+func (e *GlueExporter) Enabled() bool {
+	return e.enabled
+}
+
+// FiltersTags returns true, the collector applies the tag filter
+func (e *GlueExporter) FiltersTags() bool {
+	return true
+}
+
+// Preflight checks the IAM permissions of the collector with a single call
+func (e *GlueExporter) Preflight() (string, error) {
+	_, err := glue.New(e.sess).GetJobs(&glue.GetJobsInput{MaxResults: aws.Int64(1)})
+	return "glue:GetJobs", err
+}
+
+// Describe is used by the Prometheus client to return a description of the metrics
+func (e *GlueExporter) Describe(ch chan<- *prometheus.Desc) {
+	ch <- e.CrawlerLastRunStatus
+	ch <- e.CrawlerState
+	ch <- e.JobInfo
+	ch <- e.ResourcesTotal
+}
+
+// Collect is used by the Prometheus client to collect and return the metrics values
+func (e *GlueExporter) Collect(ch chan<- prometheus.Metric) {
+	region := sessionRegion(e.sess)
+	svc := glue.New(e.sess)
+	e.collectJobs(ch, region, svc)
+	e.collectCrawlers(ch, region, svc)
+}
+
+// collectJobs collects the information of all the jobs
+func (e *GlueExporter) collectJobs(ch chan<- prometheus.Metric, region string, svc *glue.Glue) {
+	// The Resource Groups Tagging API filters the jobs on their tags, the jobs are listed without their ARN
+	var taggedJobs map[string]bool
+	if tagFilter != nil {
+		names, err := tagFilter.ResourceIDs(e.sess, "glue:job")
+		if err != nil {
+			level.Error(e.logger).Log("msg", "Call to GetResources failed", "region", region, "err", err)
+			return
+		}
+		taggedJobs = names
+	}
+
+	input := &glue.GetJobsInput{}
+
+	// Get all jobs.
+	// If a NextToken is found, do pagination until last page
+	var jobs []*glue.Job
+	for {
+		exporterMetrics.IncrementRequests(glue.ServiceName, "GetJobs")
+		result, err := svc.GetJobs(input)
+		if err != nil {
+			level.Error(e.logger).Log("msg", "Call to GetJobs failed", "region", region, "err", err)
+			exporterMetrics.IncrementErrors(glue.ServiceName, "GetJobs", err)
+			return
+		}
+		for _, job := range result.Jobs {
+			if taggedJobs != nil && !taggedJobs[aws.StringValue(job.Name)] {
+				continue
+			}
+			jobs = append(jobs, job)
+		}
+		input.NextToken = result.NextToken
+		if result.NextToken == nil {
+			break
+		}
+	}
+	ch <- prometheus.MustNewConstMetric(e.ResourcesTotal, prometheus.GaugeValue, float64(len(jobs)), region)
+
+	for _, job := range jobs {
+		var commandName string
+		if job.Command != nil {
+			commandName = aws.StringValue(job.Command.Name)
+		}
+		ch <- prometheus.MustNewConstMetric(e.JobInfo, prometheus.GaugeValue, 1, region, aws.StringValue(job.Name), commandName)
+	}
+}
+
+// collectCrawlers collects the state and last run status of all the crawlers
+func (e *GlueExporter) collectCrawlers(ch chan<- prometheus.Metric, region string, svc *glue.Glue) {
+	// The Resource Groups Tagging API filters the crawlers on their tags, the crawlers are listed without their ARN
+	var taggedCrawlers map[string]bool
+	if tagFilter != nil {
+		names, err := tagFilter.ResourceIDs(e.sess, "glue:crawler")
+		if err != nil {
+			level.Error(e.logger).Log("msg", "Call to GetResources failed", "region", region, "err", err)
+			return
+		}
+		taggedCrawlers = names
+	}
+
+	input := &glue.GetCrawlersInput{}
+
+	// Get all crawlers.
+	// If a NextToken is found, do pagination until last page
+	var crawlers []*glue.Crawler
+	for {
+		exporterMetrics.IncrementRequests(glue.ServiceName, "GetCrawlers")
+		result, err := svc.GetCrawlers(input)
+		if err != nil {
+			level.Error(e.logger).Log("msg", "Call to GetCrawlers failed", "region", region, "err", err)
+			exporterMetrics.IncrementErrors(glue.ServiceName, "GetCrawlers", err)
+			return
+		}
+		for _, crawler := range result.Crawlers {
+			if taggedCrawlers != nil && !taggedCrawlers[aws.StringValue(crawler.Name)] {
+				continue
+			}
+			crawlers = append(crawlers, crawler)
+		}
+		input.NextToken = result.NextToken
+		if result.NextToken == nil {
+			break
+		}
+	}
+
+	for _, crawler := range crawlers {
+		ch <- prometheus.MustNewConstMetric(e.CrawlerState, prometheus.GaugeValue, 1, region, aws.StringValue(crawler.Name), aws.StringValue(crawler.State))
+		// LastCrawl is not set until the crawler runs for the first time
+		if crawler.LastCrawl != nil {
+			ch <- prometheus.MustNewConstMetric(e.CrawlerLastRunStatus, prometheus.GaugeValue, 1, region, aws.StringValue(crawler.Name), aws.StringValue(crawler.LastCrawl.Status))
+		}
+	}
+}
@@ -3,12 +3,11 @@ module github.com/app-sre/aws-resource-exporter
 go 1.12
 
 require (
-	github.com/aws/aws-sdk-go v1.29.2
-	github.com/go-kit/kit v0.9.0
-	github.com/golang/protobuf v1.3.3 // indirect
-	github.com/prometheus/client_golang v1.4.1
-	github.com/prometheus/common v0.9.1
-	golang.org/x/sys v0.0.0-20200212091648-12a6c2dcc1e4 // indirect
-	golang.org/x/text v0.3.2 // indirect
+	github.com/aws/aws-sdk-go v1.55.5
+	github.com/go-kit/kit v0.10.0
+	github.com/prometheus/client_golang v1.7.1
+	github.com/prometheus/common v0.15.0
+	github.com/prometheus/exporter-toolkit v0.5.1
 	gopkg.in/alecthomas/kingpin.v2 v2.2.6
+	gopkg.in/yaml.v2 v2.4.0
 )
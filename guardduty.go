@@ -0,0 +1,151 @@
+package main
+
+import (
+	"strconv"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/guardduty"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// guardDutySeverity returns the severity bucket of a GuardDuty severity score,
+// following the ranges documented by AWS: low below 4, medium below 7 and high above
+func guardDutySeverity(score float64) string {
+	switch {
+	case score >= 7:
+		return "high"
+	case score >= 4:
+		return "medium"
+	default:
+		return "low"
+	}
+}
+
+// GuardDutyExporter defines an instance of the GuardDuty Exporter
+type GuardDutyExporter struct {
+	sess           *session.Session
+	FindingsCount  *prometheus.Desc
+	ResourcesTotal *prometheus.Desc
+
+	enabled bool
+	logger  log.Logger
+}
+
+func init() {
+	RegisterCollector(func(sess *session.Session, namespace string, logger log.Logger) Collector {
+		return NewGuardDutyExporter(sess, namespace, logger, *guardDutyEnabled)
+	})
+}
+
+// NewGuardDutyExporter creates a new GuardDutyExporter instance
+func NewGuardDutyExporter(sess *session.Session, namespace string, logger log.Logger, enabled bool) *GuardDutyExporter {
+	return &GuardDutyExporter{
+		sess:    sess,
+		enabled: enabled,
+		FindingsCount: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "guardduty_findings_count"),
+			"The number of current findings of the detector by severity.",
+			[]string{"aws_region", "detector_id", "severity"},
+			nil,
+		),
+		ResourcesTotal: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "guardduty_resources_total"),
+			"The number of GuardDuty detectors listed by the collector.",
+			[]string{"aws_region"},
+			nil,
+		),
+		logger: logger,
+	}
+}
+
+// Name returns the name of the collector
+func (e *GuardDutyExporter) Name() string {
+	return "guardduty"
+}
+
+// Enabled returns true if the collector has to be registered
+func (e *GuardDutyExporter) Enabled() bool {
+	return e.enabled
+}
+
+// Preflight checks the IAM permissions of the collector with a single call
+func (e *GuardDutyExporter) Preflight() (string, error) {
+	_, err := guardduty.New(e.sess).ListDetectors(&guardduty.ListDetectorsInput{MaxResults: aws.Int64(1)})
+	return "guardduty:ListDetectors", err
+}
+
+// Describe is used by the Prometheus client to return a description of the metrics
+func (e *GuardDutyExporter) Describe(ch chan<- *prometheus.Desc) {
+	ch <- e.FindingsCount
+	ch <- e.ResourcesTotal
+}
+
+// Collect is used by the Prometheus client to collect and return the metrics values
+func (e *GuardDutyExporter) Collect(ch chan<- prometheus.Metric) {
+	region := sessionRegion(e.sess)
+	svc := guardduty.New(e.sess)
+	input := &guardduty.ListDetectorsInput{}
+
+	// Get all detectors.
+	// If a NextToken is found, do pagination until last page
+	var detectorIDs []*string
+	for {
+		exporterMetrics.IncrementRequests(guardduty.ServiceName, "ListDetectors")
+		result, err := svc.ListDetectors(input)
+		if err != nil {
+			level.Error(e.logger).Log("msg", "Call to ListDetectors failed", "region", region, "err", err)
+			exporterMetrics.IncrementErrors(guardduty.ServiceName, "ListDetectors", err)
+			return
+		}
+		detectorIDs = append(detectorIDs, result.DetectorIds...)
+		input.NextToken = result.NextToken
+		if aws.StringValue(result.NextToken) == "" {
+			break
+		}
+	}
+	ch <- prometheus.MustNewConstMetric(e.ResourcesTotal, prometheus.GaugeValue, float64(len(detectorIDs)), region)
+
+	// There is no detector in the regions where GuardDuty isn't enabled, so nothing is exported
+	if len(detectorIDs) == 0 {
+		level.Debug(e.logger).Log("msg", "GuardDuty is not enabled", "region", region)
+		return
+	}
+
+	for _, detectorID := range detectorIDs {
+		exporterMetrics.IncrementRequests(guardduty.ServiceName, "GetFindingsStatistics")
+		result, err := svc.GetFindingsStatistics(&guardduty.GetFindingsStatisticsInput{
+			DetectorId:            detectorID,
+			FindingStatisticTypes: aws.StringSlice([]string{guardduty.FindingStatisticTypeCountBySeverity}),
+			// Archived findings have been dealt with and are not counted
+			FindingCriteria: &guardduty.FindingCriteria{
+				Criterion: map[string]*guardduty.Condition{
+					"service.archived": {Equals: aws.StringSlice([]string{"false"})},
+				},
+			},
+		})
+		if err != nil {
+			level.Error(e.logger).Log("msg", "Call to GetFindingsStatistics failed", "region", region, "detector", *detectorID, "err", err)
+			exporterMetrics.IncrementErrors(guardduty.ServiceName, "GetFindingsStatistics", err)
+			continue
+		}
+
+		// Every bucket is exported, even without findings, so that alerts can rely on the series being present
+		counts := map[string]float64{"low": 0, "medium": 0, "high": 0}
+		if result.FindingStatistics != nil {
+			for severity, count := range result.FindingStatistics.CountBySeverity {
+				score, err := strconv.ParseFloat(severity, 64)
+				if err != nil {
+					level.Warn(e.logger).Log("msg", "Could not parse the finding severity", "detector", *detectorID, "severity", severity, "err", err)
+					continue
+				}
+				counts[guardDutySeverity(score)] += float64(aws.Int64Value(count))
+			}
+		}
+		for severity, count := range counts {
+			ch <- prometheus.MustNewConstMetric(e.FindingsCount, prometheus.GaugeValue, count, region, *detectorID, severity)
+		}
+	}
+}
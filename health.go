@@ -0,0 +1,120 @@
+package main
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/health"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// The AWS Health API is only served from us-east-1
+const healthAPIRegion = "us-east-1"
+
+// HealthExporter defines an instance of the AWS Health Exporter
+type HealthExporter struct {
+	sess           *session.Session
+	Event          *prometheus.Desc
+	ResourcesTotal *prometheus.Desc
+
+	enabled bool
+	logger  log.Logger
+}
+
+func init() {
+	RegisterCollector(func(sess *session.Session, namespace string, logger log.Logger) Collector {
+		return NewHealthExporter(sess, namespace, logger, *healthEnabled)
+	})
+}
+
+// NewHealthExporter creates a new HealthExporter instance
+func NewHealthExporter(sess *session.Session, namespace string, logger log.Logger, enabled bool) *HealthExporter {
+	return &HealthExporter{
+		sess:    sess,
+		enabled: enabled,
+		Event: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "health_event"),
+			"Open and upcoming AWS Health events affecting the account. The value is the event start time (UTC date timestamp).",
+			[]string{"event_arn", "service", "event_type_code", "event_type_category", "region", "status"},
+			nil,
+		),
+		ResourcesTotal: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "health_resources_total"),
+			"The number of open and upcoming AWS Health events listed by the collector.",
+			[]string{"aws_region"},
+			nil,
+		),
+		logger: logger,
+	}
+}
+
+// Name returns the name of the collector
+func (e *HealthExporter) Name() string {
+	return "health"
+}
+
+// Enabled returns true if the collector has to be registered
+// DescribeEvents requires a Business or Enterprise support plan, so the collector is opt-in
+func (e *HealthExporter) Enabled() bool {
+	return e.enabled
+}
+
+// Global returns true, the collector is only created for the first region
+func (e *HealthExporter) Global() bool {
+	return true
+}
+
+// Preflight checks the IAM permissions of the collector with a single call
+func (e *HealthExporter) Preflight() (string, error) {
+	_, err := health.New(e.sess, aws.NewConfig().WithRegion(healthAPIRegion)).DescribeEvents(&health.DescribeEventsInput{MaxResults: aws.Int64(10)})
+	return "health:DescribeEvents", err
+}
+
+// Describe is used by the Prometheus client to return a description of the metrics
+func (e *HealthExporter) Describe(ch chan<- *prometheus.Desc) {
+	ch <- e.Event
+	ch <- e.ResourcesTotal
+}
+
+// Collect is used by the Prometheus client to collect and return the metrics values
+func (e *HealthExporter) Collect(ch chan<- prometheus.Metric) {
+	svc := health.New(e.sess, aws.NewConfig().WithRegion(healthAPIRegion))
+	input := &health.DescribeEventsInput{
+		Filter: &health.EventFilter{
+			EventStatusCodes: aws.StringSlice([]string{health.EventStatusCodeOpen, health.EventStatusCodeUpcoming}),
+		},
+	}
+
+	// Get all events.
+	// If a NextToken is found, do pagination until last page
+	var events []*health.Event
+	for {
+		exporterMetrics.IncrementRequests(health.ServiceName, "DescribeEvents")
+		result, err := svc.DescribeEvents(input)
+		if err != nil {
+			level.Error(e.logger).Log("msg", "Call to DescribeEvents failed", "region", healthAPIRegion, "err", err)
+			exporterMetrics.IncrementErrors(health.ServiceName, "DescribeEvents", err)
+			return
+		}
+		events = append(events, result.Events...)
+		input.NextToken = result.NextToken
+		if result.NextToken == nil {
+			break
+		}
+	}
+
+	ch <- prometheus.MustNewConstMetric(e.ResourcesTotal, prometheus.GaugeValue, float64(len(events)), "global")
+
+	for _, event := range events {
+		if event.StartTime == nil {
+			continue
+		}
+		region := aws.StringValue(event.Region)
+		if region == "" {
+			region = "global"
+		}
+		ch <- prometheus.MustNewConstMetric(e.Event, prometheus.GaugeValue, float64(event.StartTime.Unix()),
+			*event.Arn, aws.StringValue(event.Service), aws.StringValue(event.EventTypeCode), aws.StringValue(event.EventTypeCategory), region, aws.StringValue(event.StatusCode))
+	}
+}
@@ -0,0 +1,194 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// IAM is a global service, its resources are reported in this region label
+const iamRegion = "global"
+
+// The credential report is generated asynchronously, its state is polled every
+// iamReportPollInterval up to iamReportMaxPolls times
+const (
+	iamReportPollInterval = 2 * time.Second
+	iamReportMaxPolls     = 10
+)
+
+// AWS regenerates the credential report at most every 4 hours, there is no point in fetching it on every scrape
+const iamRefreshInterval = time.Hour
+
+// IAMExporter defines an instance of the IAM Exporter
+type IAMExporter struct {
+	sess                 *session.Session
+	AccessKeyAge         *prometheus.Desc
+	MFAEnabled           *prometheus.Desc
+	PasswordLastUsedDays *prometheus.Desc
+	ResourcesTotal       *prometheus.Desc
+
+	enabled bool
+	logger  log.Logger
+}
+
+func init() {
+	RegisterCollector(func(sess *session.Session, namespace string, logger log.Logger) Collector {
+		return NewIAMExporter(sess, namespace, logger, *iamEnabled)
+	})
+}
+
+// NewIAMExporter creates a new IAMExporter instance
+func NewIAMExporter(sess *session.Session, namespace string, logger log.Logger, enabled bool) *IAMExporter {
+	return &IAMExporter{
+		sess:    sess,
+		enabled: enabled,
+		AccessKeyAge: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "iam_user_access_key_age_days"),
+			"The number of days since the active access key of the user was last rotated.",
+			[]string{"aws_region", "user", "access_key"},
+			nil,
+		),
+		MFAEnabled: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "iam_user_mfa_enabled"),
+			"Indicates if an MFA device is enabled for the user.",
+			[]string{"aws_region", "user"},
+			nil,
+		),
+		PasswordLastUsedDays: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "iam_user_password_last_used_days"),
+			"The number of days since the password of the user was last used to sign in.",
+			[]string{"aws_region", "user"},
+			nil,
+		),
+		ResourcesTotal: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "iam_resources_total"),
+			"The number of IAM users of the credential report listed by the collector, including the root account.",
+			[]string{"aws_region"},
+			nil,
+		),
+		logger: logger,
+	}
+}
+
+// Name returns the name of the collector
+func (e *IAMExporter) Name() string {
+	return "iam"
+}
+
+// Enabled returns true if the collector has to be registered
+func (e *IAMExporter) Enabled() bool {
+	return e.enabled
+}
+
+// Global returns true, the collector is only created for the first region
+func (e *IAMExporter) Global() bool {
+	return true
+}
+
+// Preflight checks the IAM permissions of the collector with a single call
+func (e *IAMExporter) Preflight() (string, error) {
+	_, err := iam.New(e.sess).GetCredentialReport(&iam.GetCredentialReportInput{})
+	return "iam:GetCredentialReport", err
+}
+
+// RefreshInterval returns the minimum duration between two fetches of the credential report
+func (e *IAMExporter) RefreshInterval() time.Duration {
+	return iamRefreshInterval
+}
+
+// Describe is used by the Prometheus client to return a description of the metrics
+func (e *IAMExporter) Describe(ch chan<- *prometheus.Desc) {
+	ch <- e.AccessKeyAge
+	ch <- e.MFAEnabled
+	ch <- e.PasswordLastUsedDays
+	ch <- e.ResourcesTotal
+}
+
+// Collect is used by the Prometheus client to collect and return the metrics values
+func (e *IAMExporter) Collect(ch chan<- prometheus.Metric) {
+	svc := iam.New(e.sess)
+
+	if err := e.generateCredentialReport(svc); err != nil {
+		level.Error(e.logger).Log("msg", "Could not generate the credential report", "region", iamRegion, "err", err)
+		return
+	}
+
+	exporterMetrics.IncrementRequests(iam.ServiceName, "GetCredentialReport")
+	result, err := svc.GetCredentialReport(&iam.GetCredentialReportInput{})
+	if err != nil {
+		level.Error(e.logger).Log("msg", "Call to GetCredentialReport failed", "region", iamRegion, "err", err)
+		exporterMetrics.IncrementErrors(iam.ServiceName, "GetCredentialReport", err)
+		return
+	}
+
+	records, err := csv.NewReader(bytes.NewReader(result.Content)).ReadAll()
+	if err != nil || len(records) == 0 {
+		level.Error(e.logger).Log("msg", "Could not parse the credential report", "region", iamRegion, "err", err)
+		return
+	}
+
+	// The first record holds the column names
+	columns := map[string]int{}
+	for i, name := range records[0] {
+		columns[name] = i
+	}
+	field := func(record []string, name string) string {
+		if i, ok := columns[name]; ok && i < len(record) {
+			return record[i]
+		}
+		return ""
+	}
+
+	ch <- prometheus.MustNewConstMetric(e.ResourcesTotal, prometheus.GaugeValue, float64(len(records)-1), iamRegion)
+
+	for _, record := range records[1:] {
+		user := field(record, "user")
+
+		mfaEnabled, _ := strconv.ParseBool(field(record, "mfa_active"))
+		if mfaEnabled {
+			ch <- prometheus.MustNewConstMetric(e.MFAEnabled, prometheus.GaugeValue, 1, iamRegion, user)
+		} else {
+			ch <- prometheus.MustNewConstMetric(e.MFAEnabled, prometheus.GaugeValue, 0, iamRegion, user)
+		}
+
+		// Dates are "N/A" or "no_information" when the credential was never set or used
+		if lastUsed, err := time.Parse(time.RFC3339, field(record, "password_last_used")); err == nil {
+			ch <- prometheus.MustNewConstMetric(e.PasswordLastUsedDays, prometheus.GaugeValue, time.Since(lastUsed).Hours()/24, iamRegion, user)
+		}
+
+		for _, key := range []string{"1", "2"} {
+			if active, _ := strconv.ParseBool(field(record, "access_key_"+key+"_active")); !active {
+				continue
+			}
+			if lastRotated, err := time.Parse(time.RFC3339, field(record, "access_key_"+key+"_last_rotated")); err == nil {
+				ch <- prometheus.MustNewConstMetric(e.AccessKeyAge, prometheus.GaugeValue, time.Since(lastRotated).Hours()/24, iamRegion, user, key)
+			}
+		}
+	}
+}
+
+// generateCredentialReport requests a new credential report and waits until it is complete
+func (e *IAMExporter) generateCredentialReport(svc *iam.IAM) error {
+	for i := 0; i < iamReportMaxPolls; i++ {
+		exporterMetrics.IncrementRequests(iam.ServiceName, "GenerateCredentialReport")
+		result, err := svc.GenerateCredentialReport(&iam.GenerateCredentialReportInput{})
+		if err != nil {
+			exporterMetrics.IncrementErrors(iam.ServiceName, "GenerateCredentialReport", err)
+			return err
+		}
+		if aws.StringValue(result.State) == iam.ReportStateTypeComplete {
+			return nil
+		}
+		time.Sleep(iamReportPollInterval)
+	}
+	return errors.New("credential report generation did not complete in time")
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/arn"
+	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
+	"github.com/aws/aws-sdk-go/aws/endpoints"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/sts"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// The identity of the credentials is not tied to a region, it is reported in this region label
+const identityRegion = "global"
+
+// GetCallerIdentity returns the identity of the credentials of the session,
+// it needs no IAM permission and fails when no credentials can be resolved
+func GetCallerIdentity(sess *session.Session) (*sts.GetCallerIdentityOutput, error) {
+	svc := sts.New(sess)
+	exporterMetrics.IncrementRequests(sts.ServiceName, "GetCallerIdentity")
+	result, err := svc.GetCallerIdentity(&sts.GetCallerIdentityInput{})
+	if err != nil {
+		exporterMetrics.IncrementErrors(sts.ServiceName, "GetCallerIdentity", err)
+		return nil, err
+	}
+	return result, nil
+}
+
+// AssumeRole is a role of another account the resources are collected from
+type AssumeRole struct {
+	ARN        string
+	ExternalID string
+}
+
+// ParseAssumeRoles parses the role-arn[,external-id] roles to assume,
+// every role must belong to a different account as the metrics of an account are only collected once
+func ParseAssumeRoles(flags []string) ([]AssumeRole, error) {
+	var roles []AssumeRole
+	accounts := map[string]string{}
+	for _, flag := range flags {
+		parts := strings.SplitN(flag, ",", 2)
+		parsed, err := arn.Parse(parts[0])
+		if err != nil {
+			return nil, fmt.Errorf("invalid role ARN in %q: %v", flag, err)
+		}
+		if other, ok := accounts[parsed.AccountID]; ok {
+			return nil, fmt.Errorf("roles %q and %q belong to the same account %s", other, parts[0], parsed.AccountID)
+		}
+		accounts[parsed.AccountID] = parts[0]
+		role := AssumeRole{ARN: parts[0]}
+		if len(parts) == 2 {
+			if parts[1] == "" {
+				return nil, fmt.Errorf("empty external ID in %q, expected role-arn[,external-id]", flag)
+			}
+			role.ExternalID = parts[1]
+		}
+		roles = append(roles, role)
+	}
+	return roles, nil
+}
+
+// Session returns a copy of the session with the credentials of the assumed role,
+// they are refreshed by the SDK before they expire
+func (r AssumeRole) Session(sess *session.Session) *session.Session {
+	credentials := stscreds.NewCredentials(sess, r.ARN, func(p *stscreds.AssumeRoleProvider) {
+		if r.ExternalID != "" {
+			p.ExternalID = aws.String(r.ExternalID)
+		}
+	})
+	return sess.Copy(aws.NewConfig().WithCredentials(credentials))
+}
+
+// Partition returns the AWS partition (aws, aws-cn or aws-us-gov) of the region,
+// falling back to the partition of the identity ARN for the regions unknown to the SDK
+func Partition(region string, identity *sts.GetCallerIdentityOutput) string {
+	if partition, ok := endpoints.PartitionForRegion(endpoints.DefaultPartitions(), region); ok {
+		return partition.ID()
+	}
+	if parsed, err := arn.Parse(aws.StringValue(identity.Arn)); err == nil {
+		return parsed.Partition
+	}
+	return endpoints.AwsPartitionID
+}
+
+// NewAccountInfo returns a gauge reporting the identity resolved at startup, so that a misconfigured exporter
+// scraping the wrong account can be spotted from its metrics
+func NewAccountInfo(namespace string, identity *sts.GetCallerIdentityOutput) prometheus.Collector {
+	accountInfo := prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "account_info",
+			Help:      "The AWS identity the exporter runs as. The value is always 1.",
+		},
+		[]string{"aws_region", "account_id", "arn", "user_id"},
+	)
+	accountInfo.WithLabelValues(identityRegion, aws.StringValue(identity.Account), aws.StringValue(identity.Arn), aws.StringValue(identity.UserId)).Set(1)
+	return accountInfo
+}
@@ -0,0 +1,44 @@
+package main
+
+import "testing"
+
+func TestParseAssumeRoles(t *testing.T) {
+	tests := []struct {
+		name    string
+		flags   []string
+		want    int
+		wantErr bool
+	}{
+		{
+			name:  "roles of different accounts",
+			flags: []string{"arn:aws:iam::111111111111:role/exporter", "arn:aws:iam::222222222222:role/exporter,external-id"},
+			want:  2,
+		},
+		{
+			name:    "roles of the same account",
+			flags:   []string{"arn:aws:iam::111111111111:role/exporter", "arn:aws:iam::111111111111:role/other"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid ARN",
+			flags:   []string{"exporter"},
+			wantErr: true,
+		},
+		{
+			name:    "empty external ID",
+			flags:   []string{"arn:aws:iam::111111111111:role/exporter,"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			roles, err := ParseAssumeRoles(tt.flags)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseAssumeRoles() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(roles) != tt.want {
+				t.Errorf("ParseAssumeRoles() returned %d roles, want %d", len(roles), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/kinesis"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// KinesisExporter defines an instance of the Kinesis Exporter
+type KinesisExporter struct {
+	sess            *session.Session
+	ResourcesTotal  *prometheus.Desc
+	RetentionPeriod *prometheus.Desc
+	ShardCount      *prometheus.Desc
+	Status          *prometheus.Desc
+
+	enabled bool
+	logger  log.Logger
+}
+
+func init() {
+	RegisterCollector(func(sess *session.Session, namespace string, logger log.Logger) Collector {
+		return NewKinesisExporter(sess, namespace, logger, *kinesisEnabled)
+	})
+}
+
+// NewKinesisExporter creates a new KinesisExporter instance
+func NewKinesisExporter(sess *session.Session, namespace string, logger log.Logger, enabled bool) *KinesisExporter {
+	return &KinesisExporter{
+		sess:    sess,
+		enabled: enabled,
+		ResourcesTotal: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "kinesis_resources_total"),
+			"The number of Kinesis streams listed by the collector.",
+			[]string{"aws_region"},
+			nil,
+		),
+		RetentionPeriod: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "kinesis_stream_retention_period_hours"),
+			"The retention period of the stream in hours.",
+			[]string{"aws_region", "stream_name"},
+			nil,
+		),
+		ShardCount: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "kinesis_stream_shard_count"),
+			"The number of open shards of the stream.",
+			[]string{"aws_region", "stream_name"},
+			nil,
+		),
+		Status: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "kinesis_stream_status"),
+			"The status of the stream.",
+			[]string{"aws_region", "stream_name", "status"},
+			nil,
+		),
+		logger: logger,
+	}
+}
+
+// Name returns the name of the collector
+func (e *KinesisExporter) Name() string {
+	return "kinesis"
+}
+
+// Enabled returns true if the collector has to be registered
+func (e *KinesisExporter) Enabled() bool {
+	return e.enabled
+}
+
+// FiltersTags returns true, the collector applies the tag filter
+func (e *KinesisExporter) FiltersTags() bool {
+	return true
+}
+
+// Preflight checks the IAM permissions of the collector with a single call
+func (e *KinesisExporter) Preflight() (string, error) {
+	_, err := kinesis.New(e.sess).ListStreams(&kinesis.ListStreamsInput{Limit: aws.Int64(1)})
+	return "kinesis:ListStreams", err
+}
+
+// Describe is used by the Prometheus client to return a description of the metrics
+func (e *KinesisExporter) Describe(ch chan<- *prometheus.Desc) {
+	ch <- e.ResourcesTotal
+	ch <- e.RetentionPeriod
+	ch <- e.ShardCount
+	ch <- e.Status
+}
+
+// Collect is used by the Prometheus client to collect and return the metrics values
+func (e *KinesisExporter) Collect(ch chan<- prometheus.Metric) {
+	region := sessionRegion(e.sess)
+	svc := kinesis.New(e.sess)
+
+	// The Resource Groups Tagging API filters the streams on their tags, the streams are listed by name
+	var taggedStreams map[string]bool
+	if tagFilter != nil {
+		names, err := tagFilter.ResourceIDs(e.sess, "kinesis:stream")
+		if err != nil {
+			level.Error(e.logger).Log("msg", "Call to GetResources failed", "region", region, "err", err)
+			return
+		}
+		taggedStreams = names
+	}
+
+	input := &kinesis.ListStreamsInput{}
+
+	// Get all stream names.
+	// If HasMoreStreams is set, do pagination from the last stream name until last page
+	var streamNames []*string
+	for {
+		exporterMetrics.IncrementRequests(kinesis.ServiceName, "ListStreams")
+		result, err := svc.ListStreams(input)
+		if err != nil {
+			level.Error(e.logger).Log("msg", "Call to ListStreams failed", "region", region, "err", err)
+			exporterMetrics.IncrementErrors(kinesis.ServiceName, "ListStreams", err)
+			return
+		}
+		for _, streamName := range result.StreamNames {
+			if taggedStreams != nil && !taggedStreams[aws.StringValue(streamName)] {
+				continue
+			}
+			streamNames = append(streamNames, streamName)
+		}
+		if !aws.BoolValue(result.HasMoreStreams) || len(result.StreamNames) == 0 {
+			break
+		}
+		input.ExclusiveStartStreamName = result.StreamNames[len(result.StreamNames)-1]
+	}
+	ch <- prometheus.MustNewConstMetric(e.ResourcesTotal, prometheus.GaugeValue, float64(len(streamNames)), region)
+
+	for _, streamName := range streamNames {
+		exporterMetrics.IncrementRequests(kinesis.ServiceName, "DescribeStreamSummary")
+		result, err := svc.DescribeStreamSummary(&kinesis.DescribeStreamSummaryInput{
+			StreamName: streamName,
+		})
+		if err != nil {
+			level.Error(e.logger).Log("msg", "Call to DescribeStreamSummary failed", "region", region, "stream", *streamName, "err", err)
+			exporterMetrics.IncrementErrors(kinesis.ServiceName, "DescribeStreamSummary", err)
+			continue
+		}
+		summary := result.StreamDescriptionSummary
+		ch <- prometheus.MustNewConstMetric(e.ShardCount, prometheus.GaugeValue, float64(aws.Int64Value(summary.OpenShardCount)), region, *streamName)
+		ch <- prometheus.MustNewConstMetric(e.RetentionPeriod, prometheus.GaugeValue, float64(aws.Int64Value(summary.RetentionPeriodHours)), region, *streamName)
+		ch <- prometheus.MustNewConstMetric(e.Status, prometheus.GaugeValue, 1, region, *streamName, aws.StringValue(summary.StreamStatus))
+	}
+}
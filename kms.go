@@ -0,0 +1,175 @@
+package main
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/kms"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// The error code returned by KMS for keys whose key policy doesn't grant access to the account, such as some AWS managed keys
+const kmsAccessDeniedErrorCode = "AccessDeniedException"
+
+// KMSExporter defines an instance of the KMS Exporter
+type KMSExporter struct {
+	sess            *session.Session
+	KeyInfo         *prometheus.Desc
+	ResourcesTotal  *prometheus.Desc
+	RotationEnabled *prometheus.Desc
+
+	enabled bool
+	logger  log.Logger
+}
+
+func init() {
+	RegisterCollector(func(sess *session.Session, namespace string, logger log.Logger) Collector {
+		return NewKMSExporter(sess, namespace, logger, *kmsEnabled)
+	})
+}
+
+// NewKMSExporter creates a new KMSExporter instance
+func NewKMSExporter(sess *session.Session, namespace string, logger log.Logger, enabled bool) *KMSExporter {
+	return &KMSExporter{
+		sess:    sess,
+		enabled: enabled,
+		KeyInfo: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "kms_key_info"),
+			"The manager and state of the KMS key. The value is always 1.",
+			[]string{"aws_region", "key_id", "key_manager", "key_state"},
+			nil,
+		),
+		ResourcesTotal: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "kms_resources_total"),
+			"The number of KMS keys listed by the collector.",
+			[]string{"aws_region"},
+			nil,
+		),
+		RotationEnabled: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "kms_key_rotation_enabled"),
+			"Indicates if automatic rotation is enabled for the symmetric customer managed KMS key, keys which can't be rotated are not reported.",
+			[]string{"aws_region", "key_id"},
+			nil,
+		),
+		logger: logger,
+	}
+}
+
+// Name returns the name of the collector
+func (e *KMSExporter) Name() string {
+	return "kms"
+}
+
+// Enabled returns true if the collector has to be registered
+func (e *KMSExporter) Enabled() bool {
+	return e.enabled
+}
+
+// FiltersTags returns true, the collector applies the tag filter
+func (e *KMSExporter) FiltersTags() bool {
+	return true
+}
+
+// Preflight checks the IAM permissions of the collector with a single call
+func (e *KMSExporter) Preflight() (string, error) {
+	_, err := kms.New(e.sess).ListKeys(&kms.ListKeysInput{Limit: aws.Int64(1)})
+	return "kms:ListKeys", err
+}
+
+// ExpectedErrors returns the error of the keys whose policy doesn't grant access to the exporter
+func (e *KMSExporter) ExpectedErrors() map[string][]string {
+	return map[string][]string{
+		"DescribeKey": {kmsAccessDeniedErrorCode},
+	}
+}
+
+// Describe is used by the Prometheus client to return a description of the metrics
+func (e *KMSExporter) Describe(ch chan<- *prometheus.Desc) {
+	ch <- e.KeyInfo
+	ch <- e.ResourcesTotal
+	ch <- e.RotationEnabled
+}
+
+// Collect is used by the Prometheus client to collect and return the metrics values
+func (e *KMSExporter) Collect(ch chan<- prometheus.Metric) {
+	region := sessionRegion(e.sess)
+	svc := kms.New(e.sess)
+
+	// The Resource Groups Tagging API filters the keys on their tags
+	var taggedKeys map[string]bool
+	if tagFilter != nil {
+		arns, err := tagFilter.ResourceARNs(e.sess, "kms")
+		if err != nil {
+			level.Error(e.logger).Log("msg", "Call to GetResources failed", "region", region, "err", err)
+			return
+		}
+		taggedKeys = arns
+	}
+
+	input := &kms.ListKeysInput{}
+
+	// Get all keys.
+	// If the list is truncated, do pagination from the NextMarker until last page
+	var keys []*kms.KeyListEntry
+	for {
+		exporterMetrics.IncrementRequests(kms.ServiceName, "ListKeys")
+		result, err := svc.ListKeys(input)
+		if err != nil {
+			level.Error(e.logger).Log("msg", "Call to ListKeys failed", "region", region, "err", err)
+			exporterMetrics.IncrementErrors(kms.ServiceName, "ListKeys", err)
+			return
+		}
+		for _, key := range result.Keys {
+			if taggedKeys != nil && !taggedKeys[aws.StringValue(key.KeyArn)] {
+				continue
+			}
+			keys = append(keys, key)
+		}
+		if !aws.BoolValue(result.Truncated) {
+			break
+		}
+		input.Marker = result.NextMarker
+	}
+	ch <- prometheus.MustNewConstMetric(e.ResourcesTotal, prometheus.GaugeValue, float64(len(keys)), region)
+
+	for _, key := range keys {
+		exporterMetrics.IncrementRequests(kms.ServiceName, "DescribeKey")
+		result, err := svc.DescribeKey(&kms.DescribeKeyInput{KeyId: key.KeyId})
+		if err != nil {
+			exporterMetrics.IncrementErrors(kms.ServiceName, "DescribeKey", err)
+			if aerr, ok := err.(awserr.Error); ok && aerr.Code() == kmsAccessDeniedErrorCode {
+				level.Debug(e.logger).Log("msg", "Skipping KMS key without access", "region", region, "key", *key.KeyId)
+				continue
+			}
+			level.Error(e.logger).Log("msg", "Call to DescribeKey failed", "region", region, "key", *key.KeyId, "err", err)
+			continue
+		}
+		metadata := result.KeyMetadata
+		ch <- prometheus.MustNewConstMetric(e.KeyInfo, prometheus.GaugeValue, 1, region, *key.KeyId, aws.StringValue(metadata.KeyManager), aws.StringValue(metadata.KeyState))
+
+		// AWS managed keys are always rotated by AWS, and rotation can't be checked for keys pending deletion
+		if aws.StringValue(metadata.KeyManager) != kms.KeyManagerTypeCustomer || aws.StringValue(metadata.KeyState) != kms.KeyStateEnabled {
+			continue
+		}
+		// Automatic rotation is only supported for the symmetric keys whose key material is generated by KMS,
+		// asymmetric, HMAC, imported and custom key store keys can't be rotated
+		if aws.StringValue(metadata.CustomerMasterKeySpec) != kms.CustomerMasterKeySpecSymmetricDefault || aws.StringValue(metadata.Origin) != kms.OriginTypeAwsKms {
+			continue
+		}
+
+		exporterMetrics.IncrementRequests(kms.ServiceName, "GetKeyRotationStatus")
+		rotation, err := svc.GetKeyRotationStatus(&kms.GetKeyRotationStatusInput{KeyId: key.KeyId})
+		if err != nil {
+			level.Error(e.logger).Log("msg", "Call to GetKeyRotationStatus failed", "region", region, "key", *key.KeyId, "err", err)
+			exporterMetrics.IncrementErrors(kms.ServiceName, "GetKeyRotationStatus", err)
+			continue
+		}
+		if aws.BoolValue(rotation.KeyRotationEnabled) {
+			ch <- prometheus.MustNewConstMetric(e.RotationEnabled, prometheus.GaugeValue, 1, region, *key.KeyId)
+		} else {
+			ch <- prometheus.MustNewConstMetric(e.RotationEnabled, prometheus.GaugeValue, 0, region, *key.KeyId)
+		}
+	}
+}
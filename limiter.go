@@ -0,0 +1,48 @@
+package main
+
+import (
+	"github.com/aws/aws-sdk-go/aws/request"
+)
+
+// RequestLimiter bounds the number of AWS API requests in flight across every collector
+type RequestLimiter struct {
+	slots chan struct{}
+}
+
+// NewRequestLimiter creates a new RequestLimiter allowing max concurrent requests.
+// A max of 0 or less disables the limit.
+func NewRequestLimiter(max int) *RequestLimiter {
+	l := &RequestLimiter{}
+	if max > 0 {
+		l.slots = make(chan struct{}, max)
+	}
+	return l
+}
+
+// Instrument adds the handlers acquiring and releasing a slot around every request attempt.
+// Clients created from a session inherit its handlers, so instrumenting the shared session covers all collectors.
+func (l *RequestLimiter) Instrument(handlers *request.Handlers) {
+	handlers.Send.PushFrontNamed(request.NamedHandler{
+		Name: "exporter.RequestLimiterAcquire",
+		Fn:   func(*request.Request) { l.acquire() },
+	})
+	// CompleteAttempt always runs once Send has started, even when the attempt failed
+	handlers.CompleteAttempt.PushBackNamed(request.NamedHandler{
+		Name: "exporter.RequestLimiterRelease",
+		Fn:   func(*request.Request) { l.release() },
+	})
+}
+
+func (l *RequestLimiter) acquire() {
+	if l.slots != nil {
+		l.slots <- struct{}{}
+	}
+	exporterMetrics.InflightRequests.Inc()
+}
+
+func (l *RequestLimiter) release() {
+	exporterMetrics.InflightRequests.Dec()
+	if l.slots != nil {
+		<-l.slots
+	}
+}
@@ -1,35 +1,152 @@
 package main
 
 import (
+	"html/template"
 	"net/http"
 	"os"
 	"os/signal"
+	"sort"
+	"strings"
 	"syscall"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
-	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/client"
+	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/rds"
+	"github.com/go-kit/kit/log"
 	"github.com/go-kit/kit/log/level"
+	"github.com/prometheus/common/expfmt"
 	"github.com/prometheus/common/promlog"
 	"github.com/prometheus/common/promlog/flag"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/prometheus/common/version"
+	"github.com/prometheus/exporter-toolkit/web"
 	"gopkg.in/alecthomas/kingpin.v2"
 )
 
 const (
-	namespace = "aws_resources_exporter"
+	defaultNamespace = "aws_resources_exporter"
 )
 
 var (
-	listenAddress = kingpin.Flag("web.listen-address", "The address to listen on for HTTP requests.").Default(":9115").String()
-	metricsPath   = kingpin.Flag("web.telemetry-path", "Path under which to expose metrics.").Default("/metrics").String()
+	listenAddress            = kingpin.Flag("web.listen-address", "The address to listen on for HTTP requests.").Default(":9115").String()
+	metricsPath              = kingpin.Flag("web.telemetry-path", "Path under which to expose metrics.").Default("/metrics").String()
+	readTimeout              = kingpin.Flag("web.read-timeout", "Maximum duration for reading an HTTP request, including its body.").Default("10s").Duration()
+	writeTimeout             = kingpin.Flag("web.write-timeout", "Maximum duration for writing an HTTP response. Has to be longer than the slowest scrape.").Default("2m").Duration()
+	idleTimeout              = kingpin.Flag("web.idle-timeout", "Maximum duration to wait for the next request on a keep-alive HTTP connection.").Default("2m").Duration()
+	webConfigFile            = kingpin.Flag("web.config.file", "Path to the exporter-toolkit web configuration file enabling TLS or basic authentication. Plain HTTP is served when it is empty.").Default("").String()
+	configFile               = kingpin.Flag("config.file", "Path to a YAML file setting the regions, enabled collectors, scrape intervals and filters. The flags set on the command line override its settings.").String()
+	dump                     = kingpin.Flag("dump", "Run the enabled collectors once, print the metrics to stdout and exit without starting the HTTP server.").Default("false").Bool()
+	metricsDurationBuckets   = kingpin.Flag("metrics.duration-buckets", "Comma separated upper bounds, in seconds, of the buckets of the collector_duration_seconds histogram.").Default("0.1,0.25,0.5,1,2.5,5,10,30,60").String()
+	metricsNamespace         = kingpin.Flag("metrics.namespace", "Namespace prepended to the name of every metric.").Default(defaultNamespace).String()
+	metricsPartition         = kingpin.Flag("metrics.partition-label", "Add a partition label (aws, aws-cn or aws-us-gov) to the metrics of the collectors, so that the series of several partitions don't collide when federated.").Default("false").Bool()
+	awsAssumeRoleFlags       = kingpin.Flag("aws.assume-role", "Role to assume to collect the resources of another account, as role-arn[,external-id]. Can be repeated to collect several accounts, labelled with aws_account_id. The exporter's own account is then not collected.").Strings()
+	awsEndpoint              = kingpin.Flag("aws.endpoint", "Override the AWS API endpoint URL, for example to use LocalStack or a VPC endpoint.").String()
+	awsMaxConcurrentRequests = kingpin.Flag("aws.max-concurrent-requests", "Maximum number of AWS API requests in flight across all collectors. 0 disables the limit.").Default("0").Int()
+	awsMaxRetries            = kingpin.Flag("aws.max-retries", "Maximum number of times the AWS SDK retries a failed or throttled API request.").Default("3").Int()
+	awsProfile               = kingpin.Flag("aws.profile", "Named profile of the shared AWS config and credentials files to get the credentials from.").String()
+	awsRegionFlags           = kingpin.Flag("aws.region", "AWS region to collect the resources of. Can be repeated to collect several regions from a single exporter. Defaults to the AWS_REGION environment variable.").Strings()
+	awsRetryBaseDelay        = kingpin.Flag("aws.retry-base-delay", "Base delay of the exponential backoff between two retries of an AWS API request.").Default("30ms").Duration()
+	awsUserAgent             = kingpin.Flag("aws.user-agent", "Appended to the User-Agent of the AWS API requests, to identify the exporter in CloudTrail.").Default("aws-resource-exporter/" + version.Version).String()
+	cloudWatchMetricFlags    = kingpin.Flag("cloudwatch.metric", "CloudWatch metric to export, as namespace=...,metric=...[,statistic=Average][,period=5m][,dimension.<name>=<value>...]. Can be repeated.").Strings()
+	apiGatewayEnabled        = kingpin.Flag("collector.apigateway", "Enable the API Gateway REST API stages collector.").Default("false").Bool()
+	autoScalingEnabled       = kingpin.Flag("collector.autoscaling", "Enable the Auto Scaling groups capacity collector.").Default("false").Bool()
+	batchEnabled             = kingpin.Flag("collector.batch", "Enable the Batch compute environments and job queues collector.").Default("false").Bool()
+	cloudFrontEnabled        = kingpin.Flag("collector.cloudfront", "Enable the CloudFront distributions collector.").Default("false").Bool()
+	cloudTrailEnabled        = kingpin.Flag("collector.cloudtrail", "Enable the CloudTrail trails status collector.").Default("false").Bool()
+	cloudWatchAlarmsEnabled  = kingpin.Flag("collector.cloudwatchalarms", "Enable the CloudWatch alarms state collector.").Default("false").Bool()
+	costEnabled              = kingpin.Flag("collector.cost", "Enable the Cost Explorer monthly spend collector (each request is billed by AWS).").Default("false").Bool()
+	dmsEnabled               = kingpin.Flag("collector.dms", "Enable the DMS replication tasks collector.").Default("false").Bool()
+	ec2Enabled               = kingpin.Flag("collector.ec2", "Enable the EC2 instances, NAT gateways and Elastic IPs collector.").Default("false").Bool()
+	ecrEnabled               = kingpin.Flag("collector.ecr", "Enable the ECR repositories collector.").Default("false").Bool()
+	ecsEnabled               = kingpin.Flag("collector.ecs", "Enable the ECS services task count collector.").Default("false").Bool()
+	efsEnabled               = kingpin.Flag("collector.efs", "Enable the EFS file systems collector.").Default("false").Bool()
+	eksEnabled               = kingpin.Flag("collector.eks", "Enable the EKS clusters and node groups collector.").Default("false").Bool()
+	elastiCacheEnabled       = kingpin.Flag("collector.elasticache", "Enable the ElastiCache replication groups collector.").Default("false").Bool()
+	elbEnabled               = kingpin.Flag("collector.elb", "Enable the Elastic Load Balancing target health collector.").Default("false").Bool()
+	eventBridgeEnabled       = kingpin.Flag("collector.eventbridge", "Enable the EventBridge rules collector.").Default("false").Bool()
+	fsxEnabled               = kingpin.Flag("collector.fsx", "Enable the FSx file systems collector.").Default("false").Bool()
+	glueEnabled              = kingpin.Flag("collector.glue", "Enable the Glue jobs and crawlers collector.").Default("false").Bool()
+	guardDutyEnabled         = kingpin.Flag("collector.guardduty", "Enable the GuardDuty findings collector.").Default("false").Bool()
+	healthEnabled            = kingpin.Flag("collector.health", "Enable the AWS Health events collector (requires a Business or Enterprise support plan).").Default("false").Bool()
+	iamEnabled               = kingpin.Flag("collector.iam", "Enable the IAM users credential report collector.").Default("false").Bool()
+	kinesisEnabled           = kingpin.Flag("collector.kinesis", "Enable the Kinesis streams collector.").Default("false").Bool()
+	kmsEnabled               = kingpin.Flag("collector.kms", "Enable the KMS keys rotation collector.").Default("false").Bool()
+	organizationsEnabled     = kingpin.Flag("collector.organizations", "Enable the Organizations accounts collector, which only works from the management account.").Default("false").Bool()
+	quotasEnabled            = kingpin.Flag("collector.quotas", "Enable the service quotas utilization collector.").Default("false").Bool()
+	rdsEnabled               = kingpin.Flag("collector.rds", "Enable the RDS instances collector.").Default("true").Bool()
+	rdsEventsEnabled         = kingpin.Flag("collector.rdsevents", "Enable the RDS events collector.").Default("false").Bool()
+	redshiftEnabled          = kingpin.Flag("collector.redshift", "Enable the Redshift clusters collector.").Default("false").Bool()
+	s3Enabled                = kingpin.Flag("collector.s3", "Enable the S3 bucket size and object count collector (requires cloudwatch:ListMetrics and cloudwatch:GetMetricData).").Default("false").Bool()
+	secretsManagerEnabled    = kingpin.Flag("collector.secretsmanager", "Enable the Secrets Manager rotation collector.").Default("false").Bool()
+	securityGroupsEnabled    = kingpin.Flag("collector.securitygroups", "Enable the security groups rule count collector.").Default("false").Bool()
+	snsEnabled               = kingpin.Flag("collector.sns", "Enable the SNS topics subscription collector.").Default("false").Bool()
+	sqsEnabled               = kingpin.Flag("collector.sqs", "Enable the SQS queue depth collector (requires cloudwatch:GetMetricData for the age of the oldest message).").Default("false").Bool()
+	storageGatewayEnabled    = kingpin.Flag("collector.storagegateway", "Enable the Storage Gateway gateways collector.").Default("false").Bool()
+	transitGatewayEnabled    = kingpin.Flag("collector.transitgateway", "Enable the Transit Gateways and attachments collector.").Default("false").Bool()
+	vpcEnabled               = kingpin.Flag("collector.vpc", "Enable the VPC, subnet and network interface inventory collector.").Default("false").Bool()
+	wafv2Enabled             = kingpin.Flag("collector.wafv2", "Enable the WAFv2 Web ACLs collector.").Default("false").Bool()
+	workSpacesEnabled        = kingpin.Flag("collector.workspaces", "Enable the WorkSpaces collector.").Default("false").Bool()
+	ecrImageTag              = kingpin.Flag("ecr.image-tag", "Tag of the ECR images whose latest scan findings are exported.").Default("latest").String()
+	rdsBooleanLabels         = kingpin.Flag("rds.boolean-labels", "Emit the boolean RDS metrics with a value=\"true\" or value=\"false\" label instead of a 0 or 1 sample value.").Default("false").Bool()
+	rdsChangedOnly           = kingpin.Flag("rds.changed-only", "Only emit the metrics of RDS instances that changed since the previous scrape.").Default("false").Bool()
+	rdsConcurrency           = kingpin.Flag("rds.concurrency", "Number of RDS instances processed in parallel during a scrape.").Default("10").Int()
+	rdsExclude               = kingpin.Flag("rds.exclude", "Regular expression of the RDS instance identifiers to skip. Takes precedence over --rds.include.").Regexp()
+	rdsInclude               = kingpin.Flag("rds.include", "Regular expression of the RDS instance identifiers to export. All instances are exported by default.").Regexp()
+	rdsFilterFlags           = kingpin.Flag("rds.filter", "DescribeDBInstances filter scoping the exported RDS instances, as name=value[,value...] (for example engine=postgres or db-cluster-id=my-cluster). Can be repeated.").Strings()
+	rdsFreeStorageSpace      = kingpin.Flag("rds.free-storage-space", "Fetch the free storage space of the RDS instances from CloudWatch (requires cloudwatch:GetMetricData).").Default("false").Bool()
+	rdsCloudWatchPeriod      = kingpin.Flag("rds.cloudwatch-period", "Period over which the RDS CloudWatch metrics are averaged.").Default("5m").Duration()
+	rdsEOLVersionFlags       = kingpin.Flag("rds.deprecated-engine-version", "Deprecated RDS engine versions, as engine=version-prefix[,version-prefix...] (for example postgres=9.,10. or mysql=5.6.). Can be repeated.").Strings()
+	rdsEngines               = kingpin.Flag("rds.engines", "Comma separated list of the RDS engines to export, for example postgres,aurora-postgresql. All engines are exported by default.").String()
+	rdsEventsLookback        = kingpin.Flag("rds.events-lookback", "Period of time over which the RDS events are counted.").Default("60m").Duration()
+	rdsTeamTagKeys           = kingpin.Flag("rds.team-tag-key", "Tag key holding the team owning an RDS instance. Can be repeated to map several tag keys to the team label, in order of precedence.").Strings()
+	preflightCheck           = kingpin.Flag("preflight.check", "Check the IAM permissions of the enabled collectors at startup with one cheap AWS call each.").Default("false").Bool()
+	preflightDisable         = kingpin.Flag("preflight.disable-collectors", "Disable the collectors failing the preflight check because of a missing IAM permission.").Default("false").Bool()
+	scrapeConcurrency        = kingpin.Flag("scrape.concurrency", "Maximum number of collectors running at the same time during a scrape. 0 runs all the collectors at once.").Default("0").Int()
+	scrapeIntervalFlags      = kingpin.Flag("scrape.interval", "Minimum interval between two fetches of the metrics of a collector from AWS, as collector=duration (for example iam=1h). The cached metrics are served in between. Can be repeated.").Strings()
+	tagFilterFlag            = kingpin.Flag("tag.filter", "Only export the resources carrying this tag, as key=value.").String()
 
 	exporterMetrics *ExporterMetrics
+	readiness       *Readiness
+	tagFilter       *TagFilter
+
+	assumeRoles       []AssumeRole
+	awsRegions        []string
+	cloudWatchMetrics []CloudWatchMetric
+	rdsFilters        []*rds.Filter
+	rdsEOLVersions    map[string][]string
+	scrapeIntervals   map[string]time.Duration
 )
 
+var landingPageTemplate = template.Must(template.New("landing").Parse(`<html>
+<head><title>AWS Resources Exporter</title></head>
+<body>
+<h1>AWS Resources Exporter</h1>
+<p>Version: {{ .Version }}</p>
+<ul>
+<li><a href="{{ .MetricsPath }}">Metrics</a></li>
+<li><a href="/healthz">Health</a></li>
+<li><a href="/ready">Readiness</a></li>
+</ul>
+<h2>Enabled collectors</h2>
+<ul>
+{{ range $name, $accounts := .Collectors }}<li>{{ $name }}{{ if $accounts }} ({{ range $i, $account := $accounts }}{{ if $i }}, {{ end }}{{ $account }}{{ end }}){{ end }}</li>
+{{ end }}</ul>
+</body>
+</html>
+`))
+
+type landingPageData struct {
+	Version     string
+	MetricsPath string
+	// Collectors are the accounts of the enabled collectors by collector name, the accounts are empty without assumed roles
+	Collectors map[string][]string
+}
+
 func main() {
 	os.Exit(run())
 }
@@ -37,54 +154,202 @@ func main() {
 func run() int {
 	promlogConfig := &promlog.Config{}
 	flag.AddFlags(kingpin.CommandLine, promlogConfig)
-	kingpin.Version(version.Print(namespace))
+	kingpin.Version(version.Print(defaultNamespace))
 	kingpin.HelpFlag.Short('h')
 	kingpin.Parse()
 	logger := promlog.New(promlogConfig)
 
-	level.Info(logger).Log("msg", "Starting"+namespace, "version", version.Info())
+	level.Info(logger).Log("msg", "Starting"+defaultNamespace, "version", version.Info())
 	level.Info(logger).Log("msg", "Build context", version.BuildContext())
 
-	awsRegion := os.Getenv("AWS_REGION")
-	if awsRegion == "" {
-		level.Error(logger).Log("msg", "AWS_REGION has to be defined")
+	if *configFile != "" {
+		config, err := LoadConfig(*configFile)
+		if err != nil {
+			level.Error(logger).Log("msg", "Could not load the configuration file", "err", err)
+			return 1
+		}
+		if err := config.Apply(kingpin.CommandLine, os.Args[1:]); err != nil {
+			level.Error(logger).Log("msg", "Could not apply the configuration file", "err", err)
+			return 1
+		}
+	}
+
+	var err error
+	tagFilter, err = ParseTagFilter(*tagFilterFlag)
+	if err != nil {
+		level.Error(logger).Log("msg", "Could not parse the tag filter", "err", err)
+		return 1
+	}
+
+	cloudWatchMetrics, err = ParseCloudWatchMetrics(*cloudWatchMetricFlags)
+	if err != nil {
+		level.Error(logger).Log("msg", "Could not parse the CloudWatch metrics", "err", err)
 		return 1
 	}
 
-	creds := credentials.NewEnvCredentials()
-	if _, err := creds.Get(); err != nil {
-		level.Error(logger).Log("msg", "Could not get AWS credentials from env variables", "err", err)
+	rdsFilters, err = ParseRDSFilters(*rdsFilterFlags)
+	if err != nil {
+		level.Error(logger).Log("msg", "Could not parse the RDS filters", "err", err)
+		return 1
+	}
+
+	rdsEOLVersions, err = ParseRDSEngineVersions(*rdsEOLVersionFlags)
+	if err != nil {
+		level.Error(logger).Log("msg", "Could not parse the deprecated RDS engine versions", "err", err)
+		return 1
+	}
+
+	scrapeIntervals, err = ParseScrapeIntervals(*scrapeIntervalFlags)
+	if err != nil {
+		level.Error(logger).Log("msg", "Could not parse the scrape intervals", "err", err)
+		return 1
+	}
+
+	durationBuckets, err := ParseHistogramBuckets(*metricsDurationBuckets)
+	if err != nil {
+		level.Error(logger).Log("msg", "Could not parse the duration buckets", "err", err)
+		return 1
+	}
+
+	if err := web.Validate(*webConfigFile); err != nil {
+		level.Error(logger).Log("msg", "Could not load the web configuration", "err", err)
+		return 1
+	}
+
+	assumeRoles, err = ParseAssumeRoles(*awsAssumeRoleFlags)
+	if err != nil {
+		level.Error(logger).Log("msg", "Could not parse the roles to assume", "err", err)
+		return 1
+	}
+
+	awsRegions = *awsRegionFlags
+	if len(awsRegions) == 0 {
+		awsRegion := os.Getenv("AWS_REGION")
+		if awsRegion == "" {
+			level.Error(logger).Log("msg", "AWS_REGION or --aws.region has to be defined")
+			return 1
+		}
+		awsRegions = []string{awsRegion}
+	}
+
+	// The shared session is bound to the first region, every collector gets a copy of it per region
+	config := aws.NewConfig().WithRegion(awsRegions[0])
+	// The SDK retries within a single API call, so the API requests and errors are counted once per call whatever the number of attempts
+	config = request.WithRetryer(config.WithMaxRetries(*awsMaxRetries), client.DefaultRetryer{
+		NumMaxRetries: *awsMaxRetries,
+		MinRetryDelay: *awsRetryBaseDelay,
+	})
+	if *awsEndpoint != "" {
+		// The region is still set so that it is signed and reported in the aws_region label
+		config = config.WithEndpoint(*awsEndpoint).WithS3ForcePathStyle(true)
+	}
+	// The credentials are resolved by the default chain: env variables, the shared config files (or the named profile,
+	// with role chaining from a source_profile), the web identity token of IRSA and the ECS or EC2 instance role.
+	// The EC2 instance metadata is queried with IMDSv2 session tokens, without falling back to IMDSv1 when no token can be fetched.
+	sess := session.Must(session.NewSessionWithOptions(session.Options{
+		Config:            *config.WithEC2MetadataEnableFallback(false),
+		Profile:           *awsProfile,
+		SharedConfigState: session.SharedConfigEnable,
+	}))
+
+	exporterMetrics = NewExporterMetrics(sess, *metricsNamespace, durationBuckets)
+	readiness = NewReadiness()
+	prometheus.MustRegister(exporterMetrics)
+	prometheus.MustRegister(version.NewCollector(*metricsNamespace))
+	NewRequestLimiter(*awsMaxConcurrentRequests).Instrument(&sess.Handlers)
+	if *awsUserAgent != "" {
+		sess.Handlers.Build.PushBack(request.MakeAddToUserAgentFreeFormHandler(*awsUserAgent))
+	}
+
+	// Failing here is clearer than every collector failing on its first call
+	identity, err := GetCallerIdentity(sess)
+	if err != nil {
+		level.Error(logger).Log("msg", "Could not resolve the AWS credentials", "err", err)
 		return 1
 	}
+	level.Info(logger).Log("msg", "Resolved the AWS identity", "account", aws.StringValue(identity.Account), "arn", aws.StringValue(identity.Arn))
 
-	config := aws.NewConfig().WithCredentials(creds).WithRegion(awsRegion)
-	sess := session.Must(session.NewSession(config))
+	// The resource metrics are registered with the partition label when enabled, the exporter's own metrics are not
+	var registerer prometheus.Registerer = prometheus.DefaultRegisterer
+	if *metricsPartition {
+		partition := Partition(*sess.Config.Region, identity)
+		level.Info(logger).Log("msg", "Adding the partition label to the metrics", "partition", partition)
+		registerer = prometheus.WrapRegistererWith(prometheus.Labels{"partition": partition}, registerer)
+	}
 
-	exporterMetrics = NewExporterMetrics(sess)
-	prometheus.MustRegister(
-		exporterMetrics,
-		NewRDSExporter(sess, logger),
-	)
+	enabledCollectors := map[string][]string{}
+	if len(assumeRoles) == 0 {
+		registerer.MustRegister(NewAccountInfo(*metricsNamespace, identity))
+		registerCollectors(sess, "", registerer, enabledCollectors, logger)
+	}
+	// Every account gets its own collectors, their exporter metrics and readiness are tracked per account
+	for _, role := range assumeRoles {
+		roleSess := role.Session(sess)
+		roleIdentity, err := GetCallerIdentity(roleSess)
+		if err != nil {
+			level.Error(logger).Log("msg", "Could not assume the role", "role", role.ARN, "err", err)
+			return 1
+		}
+		account := aws.StringValue(roleIdentity.Account)
+		level.Info(logger).Log("msg", "Assumed the role", "account", account, "arn", aws.StringValue(roleIdentity.Arn))
+		accountRegisterer := prometheus.WrapRegistererWith(prometheus.Labels{"aws_account_id": account}, registerer)
+		accountRegisterer.MustRegister(NewAccountInfo(*metricsNamespace, roleIdentity))
+		registerCollectors(roleSess, account, accountRegisterer, enabledCollectors, logger)
+	}
+	collectorNames := make([]string, 0, len(enabledCollectors))
+	for name := range enabledCollectors {
+		collectorNames = append(collectorNames, name)
+	}
+	sort.Strings(collectorNames)
+	level.Info(logger).Log("msg", "Enabled collectors", "collectors", strings.Join(collectorNames, ","))
+
+	if *dump {
+		return dumpMetrics(logger)
+	}
 
 	http.Handle(*metricsPath, promhttp.Handler())
+	http.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("OK"))
+	})
+	http.HandleFunc("/ready", func(w http.ResponseWriter, r *http.Request) {
+		if !readiness.Ready() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte("Not ready"))
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("Ready"))
+	})
 	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
-		w.Write([]byte(`<html>
-             <head><title>AWS Resources Exporter</title></head>
-             <body>
-             <h1>AWS Resources Exporter</h1>
-             <p><a href='` + *metricsPath + `'>Metrics</a></p>
-             </body>
-             </html>`))
+		if r.URL.Path != "/" {
+			http.NotFound(w, r)
+			return
+		}
+		err := landingPageTemplate.Execute(w, landingPageData{
+			Version:     version.Version,
+			MetricsPath: *metricsPath,
+			Collectors:  enabledCollectors,
+		})
+		if err != nil {
+			level.Error(logger).Log("msg", "Error rendering landing page", "err", err)
+		}
 	})
 
-	srv := http.Server{Addr: *listenAddress}
+	// Explicit timeouts keep slow or hung clients from holding connections open forever
+	srv := http.Server{
+		Addr:         *listenAddress,
+		ReadTimeout:  *readTimeout,
+		WriteTimeout: *writeTimeout,
+		IdleTimeout:  *idleTimeout,
+	}
 	srvc := make(chan struct{})
 	term := make(chan os.Signal, 1)
 	signal.Notify(term, os.Interrupt, syscall.SIGTERM)
 
 	go func() {
 		level.Info(logger).Log("msg", "Starting HTTP server", "address", *listenAddress)
-		if err := srv.ListenAndServe(); err != http.ErrServerClosed {
+		if err := web.ListenAndServe(&srv, *webConfigFile, logger); err != http.ErrServerClosed {
 			level.Error(logger).Log("msg", "Error starting HTTP server", "err", err)
 			close(srvc)
 		}
@@ -100,3 +365,40 @@ func run() int {
 		}
 	}
 }
+
+// registerCollectors creates the collectors of the session, registers the enabled ones and adds the account to
+// enabledCollectors under their names. account is the ID of the account of the assumed role, it is empty when
+// the exporter's own credentials are used and is then not added.
+func registerCollectors(sess *session.Session, account string, registerer prometheus.Registerer, enabledCollectors map[string][]string, logger log.Logger) {
+	for _, collector := range NewCollectors(sess, account, awsRegions, *metricsNamespace, logger) {
+		exporterMetrics.SetCollectorEnabled(account, collector.Name(), collector.Enabled())
+		if !collector.Enabled() {
+			continue
+		}
+		level.Info(logger).Log("msg", "Initializing collector", "collector", collector.Name())
+		readiness.Register(account, collector.Name())
+		registerer.MustRegister(collector)
+		if account == "" {
+			enabledCollectors[collector.Name()] = nil
+		} else {
+			enabledCollectors[collector.Name()] = append(enabledCollectors[collector.Name()], account)
+		}
+	}
+}
+
+// dumpMetrics gathers the registered metrics once and prints them in the Prometheus text format,
+// the metrics are the same as the ones served on the metrics path
+func dumpMetrics(logger log.Logger) int {
+	families, err := prometheus.DefaultGatherer.Gather()
+	if err != nil {
+		level.Error(logger).Log("msg", "Error gathering metrics", "err", err)
+		return 1
+	}
+	for _, family := range families {
+		if _, err := expfmt.MetricFamilyToText(os.Stdout, family); err != nil {
+			level.Error(logger).Log("msg", "Error writing metrics", "err", err)
+			return 1
+		}
+	}
+	return 0
+}
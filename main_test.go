@@ -0,0 +1,86 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestMain(m *testing.M) {
+	exporterMetrics = NewExporterMetrics(nil, defaultNamespace, prometheus.DefBuckets)
+	readiness = NewReadiness()
+	os.Exit(m.Run())
+}
+
+// collectSamples registers the collectors in a new registry and returns the value of every sample,
+// keyed by the metric name followed by its sorted labels, e.g. name{aws_region="us-east-1"}
+func collectSamples(t *testing.T, collectors ...prometheus.Collector) map[string]float64 {
+	t.Helper()
+	registry := prometheus.NewRegistry()
+	for _, collector := range collectors {
+		if err := registry.Register(collector); err != nil {
+			t.Fatalf("Register() error = %v", err)
+		}
+	}
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("Gather() error = %v", err)
+	}
+
+	samples := map[string]float64{}
+	for _, family := range families {
+		for _, metric := range family.GetMetric() {
+			labels := make([]string, 0, len(metric.GetLabel()))
+			for _, label := range metric.GetLabel() {
+				labels = append(labels, fmt.Sprintf("%s=%q", label.GetName(), label.GetValue()))
+			}
+			sort.Strings(labels)
+			key := family.GetName() + "{" + strings.Join(labels, ",") + "}"
+			switch {
+			case metric.Gauge != nil:
+				samples[key] = metric.GetGauge().GetValue()
+			case metric.Counter != nil:
+				samples[key] = metric.GetCounter().GetValue()
+			case metric.Untyped != nil:
+				samples[key] = metric.GetUntyped().GetValue()
+			case metric.Histogram != nil:
+				samples[key] = float64(metric.GetHistogram().GetSampleCount())
+			}
+		}
+	}
+	return samples
+}
+
+// countSamples returns the number of samples of the metric
+func countSamples(samples map[string]float64, name string) int {
+	count := 0
+	for key := range samples {
+		if strings.HasPrefix(key, name+"{") {
+			count++
+		}
+	}
+	return count
+}
+
+func TestLandingPageCollectors(t *testing.T) {
+	var page bytes.Buffer
+	err := landingPageTemplate.Execute(&page, landingPageData{
+		MetricsPath: "/metrics",
+		Collectors: map[string][]string{
+			"sqs": {"111111111111"},
+			"rds": {"111111111111", "222222222222"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	want := "<li>rds (111111111111, 222222222222)</li>\n<li>sqs (111111111111)</li>\n"
+	if !strings.Contains(page.String(), want) {
+		t.Errorf("landing page = %s, want the collectors %q", page.String(), want)
+	}
+}
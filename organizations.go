@@ -0,0 +1,146 @@
+package main
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/organizations"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Organizations is a global service served from us-east-1, the accounts are reported in the organizationsRegion region label
+const (
+	organizationsRegion    = "global"
+	organizationsAPIRegion = "us-east-1"
+)
+
+// OrganizationsExporter defines an instance of the Organizations Exporter
+type OrganizationsExporter struct {
+	sess           *session.Session
+	AccountInfo    *prometheus.Desc
+	AccountsTotal  *prometheus.Desc
+	ResourcesTotal *prometheus.Desc
+
+	enabled bool
+	logger  log.Logger
+}
+
+func init() {
+	RegisterCollector(func(sess *session.Session, namespace string, logger log.Logger) Collector {
+		return NewOrganizationsExporter(sess, namespace, logger, *organizationsEnabled)
+	})
+}
+
+// NewOrganizationsExporter creates a new OrganizationsExporter instance
+func NewOrganizationsExporter(sess *session.Session, namespace string, logger log.Logger, enabled bool) *OrganizationsExporter {
+	return &OrganizationsExporter{
+		sess:    sess,
+		enabled: enabled,
+		AccountInfo: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "organizations_account_info"),
+			"The email and status of the account of the organization. The value is always 1.",
+			[]string{"aws_region", "account_id", "email", "status"},
+			nil,
+		),
+		AccountsTotal: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "organizations_accounts_total"),
+			"The number of accounts of the organization by status.",
+			[]string{"aws_region", "status"},
+			nil,
+		),
+		ResourcesTotal: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "organizations_resources_total"),
+			"The number of accounts of the organization listed by the collector.",
+			[]string{"aws_region"},
+			nil,
+		),
+		logger: logger,
+	}
+}
+
+// Name returns the name of the collector
+func (e *OrganizationsExporter) Name() string {
+	return "organizations"
+}
+
+// Enabled returns true if the collector has to be registered
+func (e *OrganizationsExporter) Enabled() bool {
+	return e.enabled
+}
+
+// Global returns true, the collector is only created for the first region
+func (e *OrganizationsExporter) Global() bool {
+	return true
+}
+
+// Preflight checks the IAM permissions of the collector with a single call
+func (e *OrganizationsExporter) Preflight() (string, error) {
+	svc := organizations.New(e.sess, aws.NewConfig().WithRegion(organizationsAPIRegion))
+	_, err := svc.ListAccounts(&organizations.ListAccountsInput{MaxResults: aws.Int64(1)})
+	return "organizations:ListAccounts", err
+}
+
+// ExpectedErrors returns the errors of the accounts which are not allowed to list the accounts of the organization
+func (e *OrganizationsExporter) ExpectedErrors() map[string][]string {
+	codes := []string{organizations.ErrCodeAWSOrganizationsNotInUseException}
+	for code := range accessDeniedErrorCodes {
+		codes = append(codes, code)
+	}
+	return map[string][]string{
+		"ListAccounts": codes,
+	}
+}
+
+// Describe is used by the Prometheus client to return a description of the metrics
+func (e *OrganizationsExporter) Describe(ch chan<- *prometheus.Desc) {
+	ch <- e.AccountInfo
+	ch <- e.AccountsTotal
+	ch <- e.ResourcesTotal
+}
+
+// Collect is used by the Prometheus client to collect and return the metrics values
+func (e *OrganizationsExporter) Collect(ch chan<- prometheus.Metric) {
+	svc := organizations.New(e.sess, aws.NewConfig().WithRegion(organizationsAPIRegion))
+	input := &organizations.ListAccountsInput{}
+
+	// Get all accounts.
+	// If a NextToken is found, do pagination until last page
+	var accounts []*organizations.Account
+	for {
+		exporterMetrics.IncrementRequests(organizations.ServiceName, "ListAccounts")
+		result, err := svc.ListAccounts(input)
+		if err != nil {
+			exporterMetrics.IncrementErrors(organizations.ServiceName, "ListAccounts", err)
+			// Only the management account and the delegated administrators can list the accounts,
+			// there is nothing to collect from the other accounts
+			if aerr, ok := err.(awserr.Error); ok && (IsAccessDenied(err) || aerr.Code() == organizations.ErrCodeAWSOrganizationsNotInUseException) {
+				level.Debug(e.logger).Log("msg", "The accounts of the organization can't be listed from this account", "region", organizationsRegion, "err", err)
+				return
+			}
+			level.Error(e.logger).Log("msg", "Call to ListAccounts failed", "region", organizationsRegion, "err", err)
+			return
+		}
+		accounts = append(accounts, result.Accounts...)
+		input.NextToken = result.NextToken
+		if result.NextToken == nil {
+			break
+		}
+	}
+
+	ch <- prometheus.MustNewConstMetric(e.ResourcesTotal, prometheus.GaugeValue, float64(len(accounts)), organizationsRegion)
+
+	counts := map[string]int{
+		organizations.AccountStatusActive:    0,
+		organizations.AccountStatusSuspended: 0,
+	}
+	for _, account := range accounts {
+		status := aws.StringValue(account.Status)
+		counts[status]++
+		ch <- prometheus.MustNewConstMetric(e.AccountInfo, prometheus.GaugeValue, 1, organizationsRegion, aws.StringValue(account.Id), aws.StringValue(account.Email), status)
+	}
+	for status, count := range counts {
+		ch <- prometheus.MustNewConstMetric(e.AccountsTotal, prometheus.GaugeValue, float64(count), organizationsRegion, status)
+	}
+}
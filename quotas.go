@@ -0,0 +1,213 @@
+package main
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/rds"
+	"github.com/aws/aws-sdk-go/service/servicequotas"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// ResourceQuota links a service quota to the function counting the resources it limits
+type ResourceQuota struct {
+	ServiceCode string
+	QuotaCode   string
+	QuotaName   string
+	Count       func(sess *session.Session) (float64, error)
+}
+
+// ResourceQuotas is the list of service quotas whose utilization is exported
+var ResourceQuotas = []ResourceQuota{
+	{ServiceCode: "rds", QuotaCode: "L-7B6409FD", QuotaName: "DB instances", Count: countDBInstances},
+	{ServiceCode: "vpc", QuotaCode: "L-F678F1CE", QuotaName: "VPCs per Region", Count: countVpcs},
+	{ServiceCode: "vpc", QuotaCode: "L-E79EC296", QuotaName: "VPC security groups per Region", Count: countSecurityGroups},
+}
+
+// QuotaExporter defines an instance of the service quotas Exporter
+type QuotaExporter struct {
+	sess             *session.Session
+	QuotaLimit       *prometheus.Desc
+	QuotaUsage       *prometheus.Desc
+	QuotaUtilization *prometheus.Desc
+
+	enabled bool
+	logger  log.Logger
+}
+
+func init() {
+	RegisterCollector(func(sess *session.Session, namespace string, logger log.Logger) Collector {
+		return NewQuotaExporter(sess, namespace, logger, *quotasEnabled)
+	})
+}
+
+// NewQuotaExporter creates a new QuotaExporter instance
+func NewQuotaExporter(sess *session.Session, namespace string, logger log.Logger, enabled bool) *QuotaExporter {
+	return &QuotaExporter{
+		sess:    sess,
+		enabled: enabled,
+		QuotaLimit: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "quota_limit"),
+			"The value of the service quota.",
+			[]string{"aws_region", "service_code", "quota_name"},
+			nil,
+		),
+		QuotaUsage: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "quota_usage"),
+			"The number of resources counted against the service quota.",
+			[]string{"aws_region", "service_code", "quota_name"},
+			nil,
+		),
+		QuotaUtilization: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "quota_utilization_ratio"),
+			"The ratio of the service quota used by the current resources.",
+			[]string{"aws_region", "service_code", "quota_name"},
+			nil,
+		),
+		logger: logger,
+	}
+}
+
+// Name returns the name of the collector
+func (e *QuotaExporter) Name() string {
+	return "quotas"
+}
+
+// Enabled returns true if the collector has to be registered
+func (e *QuotaExporter) Enabled() bool {
+	return e.enabled
+}
+
+// ExpectedErrors returns the error of the quotas which were never adjusted, their default value is fetched instead
+func (e *QuotaExporter) ExpectedErrors() map[string][]string {
+	return map[string][]string{
+		"GetServiceQuota": {servicequotas.ErrCodeNoSuchResourceException},
+	}
+}
+
+// Describe is used by the Prometheus client to return a description of the metrics
+func (e *QuotaExporter) Describe(ch chan<- *prometheus.Desc) {
+	ch <- e.QuotaLimit
+	ch <- e.QuotaUsage
+	ch <- e.QuotaUtilization
+}
+
+// Collect is used by the Prometheus client to collect and return the metrics values
+func (e *QuotaExporter) Collect(ch chan<- prometheus.Metric) {
+	region := sessionRegion(e.sess)
+	svc := servicequotas.New(e.sess)
+
+	for _, quota := range ResourceQuotas {
+		limit, err := e.getQuotaValue(svc, quota)
+		if err != nil {
+			level.Error(e.logger).Log("msg", "Could not get service quota", "region", region, "service", quota.ServiceCode, "quota", quota.QuotaCode, "err", err)
+			continue
+		}
+
+		usage, err := quota.Count(e.sess)
+		if err != nil {
+			level.Error(e.logger).Log("msg", "Could not count resources", "region", region, "service", quota.ServiceCode, "quota", quota.QuotaCode, "err", err)
+			continue
+		}
+
+		ch <- prometheus.MustNewConstMetric(e.QuotaLimit, prometheus.GaugeValue, limit, region, quota.ServiceCode, quota.QuotaName)
+		ch <- prometheus.MustNewConstMetric(e.QuotaUsage, prometheus.GaugeValue, usage, region, quota.ServiceCode, quota.QuotaName)
+		if limit > 0 {
+			ch <- prometheus.MustNewConstMetric(e.QuotaUtilization, prometheus.GaugeValue, usage/limit, region, quota.ServiceCode, quota.QuotaName)
+		}
+	}
+}
+
+// getQuotaValue returns the applied value of the quota, falling back to the AWS default
+// when the quota was never adjusted for the account
+func (e *QuotaExporter) getQuotaValue(svc *servicequotas.ServiceQuotas, quota ResourceQuota) (float64, error) {
+	exporterMetrics.IncrementRequests(servicequotas.ServiceName, "GetServiceQuota")
+	result, err := svc.GetServiceQuota(&servicequotas.GetServiceQuotaInput{
+		ServiceCode: aws.String(quota.ServiceCode),
+		QuotaCode:   aws.String(quota.QuotaCode),
+	})
+	if err == nil {
+		return aws.Float64Value(result.Quota.Value), nil
+	}
+	if aerr, ok := err.(awserr.Error); !ok || aerr.Code() != servicequotas.ErrCodeNoSuchResourceException {
+		exporterMetrics.IncrementErrors(servicequotas.ServiceName, "GetServiceQuota", err)
+		return 0, err
+	}
+
+	exporterMetrics.IncrementRequests(servicequotas.ServiceName, "GetAWSDefaultServiceQuota")
+	defaultResult, err := svc.GetAWSDefaultServiceQuota(&servicequotas.GetAWSDefaultServiceQuotaInput{
+		ServiceCode: aws.String(quota.ServiceCode),
+		QuotaCode:   aws.String(quota.QuotaCode),
+	})
+	if err != nil {
+		exporterMetrics.IncrementErrors(servicequotas.ServiceName, "GetAWSDefaultServiceQuota", err)
+		return 0, err
+	}
+	return aws.Float64Value(defaultResult.Quota.Value), nil
+}
+
+func countDBInstances(sess *session.Session) (float64, error) {
+	svc := rds.New(sess)
+	input := &rds.DescribeDBInstancesInput{}
+
+	var count int
+	for {
+		exporterMetrics.IncrementRequests(rds.ServiceName, "DescribeDBInstances")
+		result, err := svc.DescribeDBInstances(input)
+		if err != nil {
+			exporterMetrics.IncrementErrors(rds.ServiceName, "DescribeDBInstances", err)
+			return 0, err
+		}
+		count += len(result.DBInstances)
+		input.Marker = result.Marker
+		if result.Marker == nil {
+			break
+		}
+	}
+	return float64(count), nil
+}
+
+func countVpcs(sess *session.Session) (float64, error) {
+	svc := ec2.New(sess)
+	input := &ec2.DescribeVpcsInput{}
+
+	var count int
+	for {
+		exporterMetrics.IncrementRequests(ec2.ServiceName, "DescribeVpcs")
+		result, err := svc.DescribeVpcs(input)
+		if err != nil {
+			exporterMetrics.IncrementErrors(ec2.ServiceName, "DescribeVpcs", err)
+			return 0, err
+		}
+		count += len(result.Vpcs)
+		input.NextToken = result.NextToken
+		if result.NextToken == nil {
+			break
+		}
+	}
+	return float64(count), nil
+}
+
+func countSecurityGroups(sess *session.Session) (float64, error) {
+	svc := ec2.New(sess)
+	input := &ec2.DescribeSecurityGroupsInput{}
+
+	var count int
+	for {
+		exporterMetrics.IncrementRequests(ec2.ServiceName, "DescribeSecurityGroups")
+		result, err := svc.DescribeSecurityGroups(input)
+		if err != nil {
+			exporterMetrics.IncrementErrors(ec2.ServiceName, "DescribeSecurityGroups", err)
+			return 0, err
+		}
+		count += len(result.SecurityGroups)
+		input.NextToken = result.NextToken
+		if result.NextToken == nil {
+			break
+		}
+	}
+	return float64(count), nil
+}
@@ -1,10 +1,20 @@
 package main
 
 import (
+	"fmt"
+	"hash/fnv"
+	"regexp"
+	"strconv"
+	"strings"
 	"sync"
+	"time"
 
+	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/cloudwatch"
+	"github.com/aws/aws-sdk-go/service/cloudwatch/cloudwatchiface"
 	"github.com/aws/aws-sdk-go/service/rds"
+	"github.com/aws/aws-sdk-go/service/rds/rdsiface"
 	"github.com/go-kit/kit/log"
 	"github.com/go-kit/kit/log/level"
 	"github.com/prometheus/client_golang/prometheus"
@@ -31,35 +41,257 @@ var DBMaxConnections = map[string]map[string]int64{
 	},
 }
 
+// RDSNormalizationFactors maps the instance sizes to their normalization factor.
+// Size-flexible reserved DB instances apply to every size of an instance family in proportion of these units.
+var RDSNormalizationFactors = map[string]float64{
+	"micro":    0.5,
+	"small":    1,
+	"medium":   2,
+	"large":    4,
+	"xlarge":   8,
+	"2xlarge":  16,
+	"4xlarge":  32,
+	"8xlarge":  64,
+	"10xlarge": 80,
+	"12xlarge": 96,
+	"16xlarge": 128,
+	"24xlarge": 192,
+	"32xlarge": 256,
+}
+
+// gp3 volumes smaller than gp3BaselineStorageThreshold GiB are capped at the baseline performance,
+// any IOPS configured above gp3BaselineIops or throughput above gp3BaselineThroughput MiB/s are not applied
+const (
+	gp3BaselineStorageThreshold = 400
+	gp3BaselineIops             = 3000
+	gp3BaselineThroughput       = 125
+)
+
 // RDSExporter defines an instance of the RDS Exporter
 type RDSExporter struct {
-	sess                       *session.Session
-	AllocatedStorage           *prometheus.Desc
-	DBInstanceClass            *prometheus.Desc
-	DBInstanceStatus           *prometheus.Desc
-	EngineVersion              *prometheus.Desc
-	LatestRestorableTime       *prometheus.Desc
-	MaxConnections             *prometheus.Desc
-	MaxConnectionsMappingError *prometheus.Desc
-	PubliclyAccessible         *prometheus.Desc
-	StorageEncrypted           *prometheus.Desc
+	sess                            *session.Session
+	svc                             rdsiface.RDSAPI
+	cwSvc                           cloudwatchiface.CloudWatchAPI
+	AllocatedStorage                *prometheus.Desc
+	BackupRetentionPeriod           *prometheus.Desc
+	CARotationRequired              *prometheus.Desc
+	ClusterBacktrackWindow          *prometheus.Desc
+	ClusterInfo                     *prometheus.Desc
+	ClusterMemberCount              *prometheus.Desc
+	ClusterMemberIsWriter           *prometheus.Desc
+	CopyTagsToSnapshot              *prometheus.Desc
+	DBInstanceClass                 *prometheus.Desc
+	DBInstanceStatus                *prometheus.Desc
+	DeletionProtection              *prometheus.Desc
+	EngineVersion                   *prometheus.Desc
+	EngineVersionDeprecated         *prometheus.Desc
+	FreeStorageSpace                *prometheus.Desc
+	GP3BaselineCapped               *prometheus.Desc
+	InstanceAge                     *prometheus.Desc
+	InstanceHeartbeat               *prometheus.Desc
+	InstanceInfo                    *prometheus.Desc
+	InstanceScrapeError             *prometheus.Desc
+	InstanceTeamInfo                *prometheus.Desc
+	InstancesTotal                  *prometheus.Desc
+	Iops                            *prometheus.Desc
+	LatestRestorableTime            *prometheus.Desc
+	MaxAllocatedStorage             *prometheus.Desc
+	MaxAllocatedStorageRatio        *prometheus.Desc
+	MaxConnections                  *prometheus.Desc
+	MaxConnectionsMappingError      *prometheus.Desc
+	MultiAZ                         *prometheus.Desc
+	OldestSnapshotAge               *prometheus.Desc
+	OptionGroup                     *prometheus.Desc
+	OptionGroupPending              *prometheus.Desc
+	ParameterApplyStatus            *prometheus.Desc
+	PerformanceInsightsEnabled      *prometheus.Desc
+	PerformanceInsightsRetention    *prometheus.Desc
+	PubliclyAccessible              *prometheus.Desc
+	ReadReplicaCount                *prometheus.Desc
+	ReadReplicaInfo                 *prometheus.Desc
+	ReservedInstanceCount           *prometheus.Desc
+	ReservedInstanceExpiry          *prometheus.Desc
+	ReservedInstanceFamilyCount     *prometheus.Desc
+	ReservedInstanceNormalizedUnits *prometheus.Desc
+	SnapshotCount                   *prometheus.Desc
+	SnapshotProgress                *prometheus.Desc
+	StorageAutoscalingEnabled       *prometheus.Desc
+	StorageEncrypted                *prometheus.Desc
+	StorageType                     *prometheus.Desc
+	StorageUsedRatio                *prometheus.Desc
+
+	options        RDSOptions
+	instanceHashes map[string]uint64
 
 	logger log.Logger
 	mutex  *sync.Mutex
 }
 
+// ParseRDSFilters parses DescribeDBInstances filters such as engine=postgres,mysql
+func ParseRDSFilters(filters []string) ([]*rds.Filter, error) {
+	var parsed []*rds.Filter
+	for _, filter := range filters {
+		parts := strings.SplitN(filter, "=", 2)
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return nil, fmt.Errorf("invalid RDS filter %q, expected name=value[,value...]", filter)
+		}
+		parsed = append(parsed, &rds.Filter{
+			Name:   aws.String(parts[0]),
+			Values: aws.StringSlice(strings.Split(parts[1], ",")),
+		})
+	}
+	return parsed, nil
+}
+
+// rdsEngineSet returns the set of engines of a comma separated list
+func rdsEngineSet(engines string) map[string]bool {
+	set := map[string]bool{}
+	for _, engine := range strings.Split(engines, ",") {
+		if engine = strings.TrimSpace(engine); engine != "" {
+			set[engine] = true
+		}
+	}
+	return set
+}
+
+// ParseRDSEngineVersions parses lists of engine version prefixes such as postgres=9.,10.
+func ParseRDSEngineVersions(flags []string) (map[string][]string, error) {
+	versions := map[string][]string{}
+	for _, flag := range flags {
+		parts := strings.SplitN(flag, "=", 2)
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return nil, fmt.Errorf("invalid RDS engine versions %q, expected engine=version[,version...]", flag)
+		}
+		versions[parts[0]] = append(versions[parts[0]], strings.Split(parts[1], ",")...)
+	}
+	return versions, nil
+}
+
+// rdsExpiringCAs are the CA certificates AWS is retiring, the instances still using them have to be rotated
+var rdsExpiringCAs = map[string]bool{
+	"rds-ca-2015": true,
+	"rds-ca-2019": true,
+}
+
+// The pending maintenance action scheduled by AWS to rotate the CA certificate of an instance
+const rdsCARotationAction = "ca-certificate-rotation"
+
+// The stage of the collection of the metrics of a DB instance which can fail on its own
+const rdsScrapeStageTags = "tags"
+
+// RDSOptions holds the settings of the RDS exporter
+type RDSOptions struct {
+	// Enabled registers the collector, it is the only collector enabled by default
+	Enabled bool
+	// ChangedOnly only emits the instance metrics when the instance changed since the previous scrape
+	ChangedOnly bool
+	// TeamTagKeys are the tag keys holding the team owning an instance, in order of precedence
+	TeamTagKeys []string
+	// Concurrency is the number of instances processed in parallel
+	Concurrency int
+	// FreeStorageSpace fetches the FreeStorageSpace CloudWatch metric of every instance
+	FreeStorageSpace bool
+	// CloudWatchPeriod is the period over which the CloudWatch metrics are averaged
+	CloudWatchPeriod time.Duration
+	// Filters are passed to DescribeDBInstances to scope the instances server-side
+	Filters []*rds.Filter
+	// Engines only keeps the instances running one of these engines, all engines are kept when empty
+	Engines map[string]bool
+	// Include only keeps the instances whose identifier matches, all instances are kept when nil
+	Include *regexp.Regexp
+	// Exclude drops the instances whose identifier matches, it takes precedence over Include
+	Exclude *regexp.Regexp
+	// DeprecatedEngineVersions maps an engine to the prefixes of its deprecated versions
+	DeprecatedEngineVersions map[string][]string
+	// BooleanLabels emits the boolean metrics with a value="true" or value="false" label and a sample value of 1
+	BooleanLabels bool
+}
+
+func init() {
+	RegisterCollector(func(sess *session.Session, namespace string, logger log.Logger) Collector {
+		return NewRDSExporter(sess, rds.New(sess), cloudwatch.New(sess), namespace, logger, RDSOptions{
+			Enabled:          *rdsEnabled,
+			ChangedOnly:      *rdsChangedOnly,
+			TeamTagKeys:      *rdsTeamTagKeys,
+			Concurrency:      *rdsConcurrency,
+			FreeStorageSpace: *rdsFreeStorageSpace,
+			CloudWatchPeriod: *rdsCloudWatchPeriod,
+			Filters:          rdsFilters,
+			Engines:          rdsEngineSet(*rdsEngines),
+			Include:          *rdsInclude,
+			Exclude:          *rdsExclude,
+			BooleanLabels:    *rdsBooleanLabels,
+
+			DeprecatedEngineVersions: rdsEOLVersions,
+		})
+	})
+}
+
 // NewRDSExporter creates a new RDSExporter instance
-func NewRDSExporter(sess *session.Session, logger log.Logger) *RDSExporter {
-	level.Info(logger).Log("msg", "Initializing RDS exporter")
+// All the RDS and CloudWatch API calls are made through svc and cwSvc, which can be replaced by fake implementations in tests
+func NewRDSExporter(sess *session.Session, svc rdsiface.RDSAPI, cwSvc cloudwatchiface.CloudWatchAPI, namespace string, logger log.Logger, options RDSOptions) *RDSExporter {
+	// With BooleanLabels the boolean metrics carry their state in a value label instead of their sample value
+	boolLabels := func(labels ...string) []string {
+		if options.BooleanLabels {
+			return append(labels, "value")
+		}
+		return labels
+	}
 	return &RDSExporter{
-		sess:  sess,
-		mutex: &sync.Mutex{},
+		sess:           sess,
+		svc:            svc,
+		cwSvc:          cwSvc,
+		options:        options,
+		instanceHashes: map[string]uint64{},
+		mutex:          &sync.Mutex{},
 		AllocatedStorage: prometheus.NewDesc(
 			prometheus.BuildFQName(namespace, "", "rds_allocatedstorage"),
 			"The amount of allocated storage in bytes.",
 			[]string{"aws_region", "dbinstance_identifier"},
 			nil,
 		),
+		BackupRetentionPeriod: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "rds_backup_retention_period_days"),
+			"The number of days for which automated backups are retained.",
+			[]string{"aws_region", "dbinstance_identifier"},
+			nil,
+		),
+		CARotationRequired: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "rds_ca_rotation_required"),
+			"Indicates if the DB instance has to be rotated to a newer CA certificate.",
+			boolLabels("aws_region", "dbinstance_identifier", "ca_certificate_identifier"),
+			nil,
+		),
+		ClusterBacktrackWindow: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "rds_cluster_backtrack_window_seconds"),
+			"The target backtrack window of the Aurora DB cluster in seconds, 0 when backtracking is disabled.",
+			[]string{"aws_region", "dbcluster_identifier"},
+			nil,
+		),
+		ClusterInfo: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "rds_cluster_info"),
+			"The engine, engine version and status of the DB cluster. The value is always 1.",
+			[]string{"aws_region", "dbcluster_identifier", "engine", "engine_version", "status"},
+			nil,
+		),
+		ClusterMemberCount: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "rds_cluster_member_count"),
+			"The number of DB instances in the DB cluster.",
+			[]string{"aws_region", "dbcluster_identifier"},
+			nil,
+		),
+		ClusterMemberIsWriter: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "rds_cluster_member_is_writer"),
+			"Indicates if the DB instance is the writer of the DB cluster.",
+			boolLabels("aws_region", "dbcluster_identifier", "dbinstance_identifier"),
+			nil,
+		),
+		CopyTagsToSnapshot: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "rds_copy_tags_to_snapshot_enabled"),
+			"Indicates if the tags of the DB instance are copied to its snapshots.",
+			boolLabels("aws_region", "dbinstance_identifier"),
+			nil,
+		),
 		DBInstanceClass: prometheus.NewDesc(
 			prometheus.BuildFQName(namespace, "", "rds_dbinstanceclass"),
 			"The DB instance class (type).",
@@ -72,18 +304,96 @@ func NewRDSExporter(sess *session.Session, logger log.Logger) *RDSExporter {
 			[]string{"aws_region", "dbinstance_identifier", "instance_status"},
 			nil,
 		),
+		DeletionProtection: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "rds_deletion_protection_enabled"),
+			"Indicates if deletion protection is enabled for the DB instance.",
+			boolLabels("aws_region", "dbinstance_identifier"),
+			nil,
+		),
 		EngineVersion: prometheus.NewDesc(
 			prometheus.BuildFQName(namespace, "", "rds_engineversion"),
 			"The DB engine type and version.",
 			[]string{"aws_region", "dbinstance_identifier", "engine", "engine_version"},
 			nil,
 		),
+		EngineVersionDeprecated: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "rds_engine_version_deprecated"),
+			"Indicates if the DB engine version is in the configured list of deprecated versions.",
+			boolLabels("aws_region", "dbinstance_identifier", "engine", "engine_version"),
+			nil,
+		),
+		FreeStorageSpace: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "rds_free_storage_space_bytes"),
+			"The amount of available storage space in bytes, from the FreeStorageSpace CloudWatch metric.",
+			[]string{"aws_region", "dbinstance_identifier"},
+			nil,
+		),
+		GP3BaselineCapped: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "rds_gp3_baseline_capped"),
+			"Indicates a gp3 DB instance below the baseline storage threshold with provisioned IOPS or throughput above the baseline, which are not applied.",
+			boolLabels("aws_region", "dbinstance_identifier"),
+			nil,
+		),
+		InstanceAge: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "rds_instance_age_seconds"),
+			"The time elapsed since the DB instance was created in seconds.",
+			[]string{"aws_region", "dbinstance_identifier"},
+			nil,
+		),
+		InstanceHeartbeat: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "rds_instance_heartbeat"),
+			"Emitted for every DB instance on each scrape, even when its other metrics are skipped because it did not change.",
+			[]string{"aws_region", "dbinstance_identifier"},
+			nil,
+		),
+		InstanceInfo: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "rds_instance_info"),
+			"The availability zones of the DB instance.",
+			[]string{"aws_region", "dbinstance_identifier", "availability_zone", "secondary_availability_zone"},
+			nil,
+		),
+		InstanceScrapeError: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "rds_instance_scrape_error"),
+			"Indicates that a stage of the collection of the DB instance metrics failed, the metrics of the stage are missing.",
+			[]string{"aws_region", "dbinstance_identifier", "stage"},
+			nil,
+		),
+		InstanceTeamInfo: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "rds_instance_team_info"),
+			"The team owning the DB instance, normalized from the configured team tag keys.",
+			[]string{"aws_region", "dbinstance_identifier", "team"},
+			nil,
+		),
+		InstancesTotal: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "rds_instances_total"),
+			"The number of DB instances listed by the collector.",
+			[]string{"aws_region"},
+			nil,
+		),
+		Iops: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "rds_iops"),
+			"The provisioned IOPS of the DB instance.",
+			[]string{"aws_region", "dbinstance_identifier"},
+			nil,
+		),
 		LatestRestorableTime: prometheus.NewDesc(
 			prometheus.BuildFQName(namespace, "", "rds_latestrestorabletime"),
 			"Latest restorable time (UTC date timestamp).",
 			[]string{"aws_region", "dbinstance_identifier"},
 			nil,
 		),
+		MaxAllocatedStorage: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "rds_max_allocated_storage_bytes"),
+			"The upper limit in bytes to which storage autoscaling can scale the storage of the DB instance.",
+			[]string{"aws_region", "dbinstance_identifier"},
+			nil,
+		),
+		MaxAllocatedStorageRatio: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "rds_allocated_to_max_storage_ratio"),
+			"The ratio of the allocated storage to the storage autoscaling upper limit.",
+			[]string{"aws_region", "dbinstance_identifier"},
+			nil,
+		),
 		MaxConnections: prometheus.NewDesc(
 			prometheus.BuildFQName(namespace, "", "rds_maxconnections"),
 			"The DB's max_connections value",
@@ -93,18 +403,126 @@ func NewRDSExporter(sess *session.Session, logger log.Logger) *RDSExporter {
 		MaxConnectionsMappingError: prometheus.NewDesc(
 			prometheus.BuildFQName(namespace, "", "rds_maxconnections_error"),
 			"Indicates no mapping found for instance/parameter group.",
-			[]string{"aws_region", "dbinstance_identifier", "instance_class"},
+			boolLabels("aws_region", "dbinstance_identifier", "instance_class"),
+			nil,
+		),
+		MultiAZ: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "rds_multi_az"),
+			"Indicates if the DB instance is a Multi-AZ deployment.",
+			boolLabels("aws_region", "dbinstance_identifier"),
+			nil,
+		),
+		OldestSnapshotAge: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "rds_oldest_snapshot_age_seconds"),
+			"The age of the oldest DB snapshot per DB instance and snapshot type.",
+			[]string{"aws_region", "dbinstance_identifier", "snapshot_type"},
+			nil,
+		),
+		OptionGroup: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "rds_option_group"),
+			"The option group attached to the DB instance. The value is always 1.",
+			[]string{"aws_region", "dbinstance_identifier", "option_group_name"},
+			nil,
+		),
+		OptionGroupPending: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "rds_option_group_pending"),
+			"Indicates if the changes of the option group are pending to be applied to the DB instance.",
+			boolLabels("aws_region", "dbinstance_identifier", "option_group_name"),
+			nil,
+		),
+		ParameterApplyStatus: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "rds_parameter_apply_status"),
+			"The status of the parameter group of the DB instance, pending-reboot when changes are not applied yet. The value is always 1.",
+			[]string{"aws_region", "dbinstance_identifier", "parameter_group", "status"},
+			nil,
+		),
+		PerformanceInsightsEnabled: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "rds_performance_insights_enabled"),
+			"Indicates if Performance Insights is enabled for the DB instance.",
+			boolLabels("aws_region", "dbinstance_identifier"),
+			nil,
+		),
+		PerformanceInsightsRetention: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "rds_performance_insights_retention_days"),
+			"The number of days the Performance Insights data is retained.",
+			[]string{"aws_region", "dbinstance_identifier"},
 			nil,
 		),
 		PubliclyAccessible: prometheus.NewDesc(
 			prometheus.BuildFQName(namespace, "", "rds_publiclyaccessible"),
 			"Indicates if the DB is publicly accessible",
+			boolLabels("aws_region", "dbinstance_identifier"),
+			nil,
+		),
+		ReadReplicaCount: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "rds_read_replica_count"),
+			"The number of read replicas of the DB instance.",
 			[]string{"aws_region", "dbinstance_identifier"},
 			nil,
 		),
+		ReadReplicaInfo: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "rds_read_replica_info"),
+			"Indicates that the DB instance is a read replica of the source DB instance.",
+			[]string{"aws_region", "dbinstance_identifier", "source_dbinstance_identifier"},
+			nil,
+		),
+		ReservedInstanceCount: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "rds_reserved_instance_count"),
+			"The number of reserved DB instances per instance class and state.",
+			[]string{"aws_region", "instance_class", "state"},
+			nil,
+		),
+		ReservedInstanceExpiry: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "rds_reserved_instance_expiry_timestamp"),
+			"Unix timestamp at which the reservation expires.",
+			[]string{"aws_region", "reserved_db_instance_id", "instance_class"},
+			nil,
+		),
+		ReservedInstanceFamilyCount: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "rds_reserved_instance_family_count"),
+			"The number of active reserved DB instances per instance family.",
+			[]string{"aws_region", "instance_family"},
+			nil,
+		),
+		ReservedInstanceNormalizedUnits: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "rds_reserved_instance_normalized_units"),
+			"The size-flexible coverage of the active reserved DB instances per instance family, in normalized units.",
+			[]string{"aws_region", "instance_family"},
+			nil,
+		),
+		SnapshotCount: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "rds_snapshot_count"),
+			"The number of DB snapshots per DB instance and snapshot type.",
+			[]string{"aws_region", "dbinstance_identifier", "snapshot_type"},
+			nil,
+		),
+		SnapshotProgress: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "rds_snapshot_progress_percent"),
+			"The percentage of the snapshot creation, copy or restore that has completed.",
+			[]string{"aws_region", "dbinstance_identifier", "snapshot_id"},
+			nil,
+		),
+		StorageAutoscalingEnabled: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "rds_storage_autoscaling_enabled"),
+			"Indicates if storage autoscaling is enabled for the DB instance.",
+			boolLabels("aws_region", "dbinstance_identifier"),
+			nil,
+		),
 		StorageEncrypted: prometheus.NewDesc(
 			prometheus.BuildFQName(namespace, "", "rds_storageencrypted"),
 			"Indicates if the DB storage is encrypted",
+			boolLabels("aws_region", "dbinstance_identifier"),
+			nil,
+		),
+		StorageType: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "rds_storage_type"),
+			"The storage type of the DB instance.",
+			[]string{"aws_region", "dbinstance_identifier", "storage_type"},
+			nil,
+		),
+		StorageUsedRatio: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "rds_storage_used_ratio"),
+			"The ratio of the allocated storage in use.",
 			[]string{"aws_region", "dbinstance_identifier"},
 			nil,
 		),
@@ -112,94 +530,716 @@ func NewRDSExporter(sess *session.Session, logger log.Logger) *RDSExporter {
 	}
 }
 
+// Name returns the name of the collector
+func (e *RDSExporter) Name() string {
+	return "rds"
+}
+
+// Enabled returns true if the collector has to be registered
+func (e *RDSExporter) Enabled() bool {
+	return e.options.Enabled
+}
+
+// FiltersTags returns true, the collector applies the tag filter
+func (e *RDSExporter) FiltersTags() bool {
+	return true
+}
+
+// Preflight checks the IAM permissions of the collector with a single call
+func (e *RDSExporter) Preflight() (string, error) {
+	_, err := e.svc.DescribeDBInstances(&rds.DescribeDBInstancesInput{MaxRecords: aws.Int64(20)})
+	return "rds:DescribeDBInstances", err
+}
+
+// ExpectedErrors returns the errors of the regions which are not enabled for the account, they are skipped
+func (e *RDSExporter) ExpectedErrors() map[string][]string {
+	codes := make([]string, 0, len(regionUnavailableErrorCodes))
+	for code := range regionUnavailableErrorCodes {
+		codes = append(codes, code)
+	}
+	return map[string][]string{
+		"DescribeDBInstances": codes,
+	}
+}
+
 // Describe is used by the Prometheus client to return a description of the metrics
 func (e *RDSExporter) Describe(ch chan<- *prometheus.Desc) {
 	ch <- e.AllocatedStorage
+	ch <- e.BackupRetentionPeriod
+	ch <- e.CARotationRequired
+	ch <- e.ClusterBacktrackWindow
+	ch <- e.ClusterInfo
+	ch <- e.ClusterMemberCount
+	ch <- e.ClusterMemberIsWriter
+	ch <- e.CopyTagsToSnapshot
 	ch <- e.DBInstanceClass
 	ch <- e.DBInstanceStatus
+	ch <- e.DeletionProtection
 	ch <- e.EngineVersion
+	ch <- e.EngineVersionDeprecated
+	ch <- e.FreeStorageSpace
+	ch <- e.GP3BaselineCapped
+	ch <- e.InstanceAge
+	ch <- e.InstanceHeartbeat
+	ch <- e.InstanceInfo
+	ch <- e.InstanceScrapeError
+	ch <- e.InstanceTeamInfo
+	ch <- e.InstancesTotal
+	ch <- e.Iops
 	ch <- e.LatestRestorableTime
+	ch <- e.MaxAllocatedStorage
+	ch <- e.MaxAllocatedStorageRatio
 	ch <- e.MaxConnections
 	ch <- e.MaxConnectionsMappingError
+	ch <- e.MultiAZ
+	ch <- e.OldestSnapshotAge
+	ch <- e.OptionGroup
+	ch <- e.OptionGroupPending
+	ch <- e.ParameterApplyStatus
+	ch <- e.PerformanceInsightsEnabled
+	ch <- e.PerformanceInsightsRetention
 	ch <- e.PubliclyAccessible
+	ch <- e.ReadReplicaCount
+	ch <- e.ReadReplicaInfo
+	ch <- e.ReservedInstanceCount
+	ch <- e.ReservedInstanceExpiry
+	ch <- e.ReservedInstanceFamilyCount
+	ch <- e.ReservedInstanceNormalizedUnits
+	ch <- e.SnapshotCount
+	ch <- e.SnapshotProgress
+	ch <- e.StorageAutoscalingEnabled
 	ch <- e.StorageEncrypted
+	ch <- e.StorageType
+	ch <- e.StorageUsedRatio
 }
 
 // Collect is used by the Prometheus client to collect and return the metrics values
 func (e *RDSExporter) Collect(ch chan<- prometheus.Metric) {
-	svc := rds.New(e.sess)
-	input := &rds.DescribeDBInstancesInput{}
+	region := sessionRegion(e.sess)
+
+	// The instances are listed first so that a region which is not enabled for the account
+	// is skipped before making the other calls, which would all fail the same way
+	instances, err := e.describeInstances(region)
+	if IsRegionUnavailable(err) {
+		level.Warn(e.logger).Log("msg", "Region is not enabled for the account, skipping it", "region", region, "err", err)
+		return
+	}
+
+	e.collectSnapshots(ch, region)
+	e.collectReservedInstances(ch, region)
+	e.collectClusters(ch, region)
+	if err != nil {
+		level.Error(e.logger).Log("msg", "Could not list the DB instances", "region", region, "err", err)
+		return
+	}
+	ch <- prometheus.MustNewConstMetric(e.InstancesTotal, prometheus.GaugeValue, float64(len(instances)), region)
+
+	// Free storage space keeps changing while the instance itself doesn't, so it is emitted for every instance
+	if e.options.FreeStorageSpace {
+		e.collectFreeStorageSpace(ch, region, instances)
+	}
+
+	var unchanged map[string]bool
+	if e.options.ChangedOnly {
+		unchanged = e.updateInstanceHashes(instances)
+	}
+
+	// Per-instance work is spread over a bounded pool of workers so that a slow instance doesn't block the others
+	workers := e.options.Concurrency
+	if workers < 1 {
+		workers = 1
+	}
+	queue := make(chan *rds.DBInstance)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for instance := range queue {
+				e.collectInstance(ch, region, instance)
+			}
+		}()
+	}
+
+	// Pending maintenance actions change while the instance itself doesn't, so the CA rotation is emitted for every instance
+	caRotations := e.pendingCARotations(region)
+	for _, instance := range instances {
+		ch <- prometheus.MustNewConstMetric(e.InstanceHeartbeat, prometheus.GaugeValue, 1, region, *instance.DBInstanceIdentifier)
+		caIdentifier := aws.StringValue(instance.CACertificateIdentifier)
+		rotationRequired := caRotations[aws.StringValue(instance.DBInstanceArn)] || rdsExpiringCAs[caIdentifier]
+		ch <- e.boolMetric(e.CARotationRequired, rotationRequired, region, *instance.DBInstanceIdentifier, caIdentifier)
+		e.collectTimes(ch, region, instance)
+		if unchanged[*instance.DBInstanceIdentifier] {
+			continue
+		}
+		queue <- instance
+	}
+	close(queue)
+	wg.Wait()
+}
+
+// pendingCARotations returns the set of ARNs of the resources with a pending CA certificate rotation,
+// it is empty when the pending maintenance actions can't be described
+func (e *RDSExporter) pendingCARotations(region string) map[string]bool {
+	input := &rds.DescribePendingMaintenanceActionsInput{}
+
+	// Get all pending maintenance actions.
+	// If a Marker is found, do pagination until last page
+	rotations := map[string]bool{}
+	for {
+		exporterMetrics.IncrementRequests(rds.ServiceName, "DescribePendingMaintenanceActions")
+		result, err := e.svc.DescribePendingMaintenanceActions(input)
+		if err != nil {
+			level.Error(e.logger).Log("msg", "Call to DescribePendingMaintenanceActions failed", "region", region, "err", err)
+			exporterMetrics.IncrementErrors(rds.ServiceName, "DescribePendingMaintenanceActions", err)
+			return rotations
+		}
+		for _, resource := range result.PendingMaintenanceActions {
+			for _, action := range resource.PendingMaintenanceActionDetails {
+				if aws.StringValue(action.Action) == rdsCARotationAction {
+					rotations[aws.StringValue(resource.ResourceIdentifier)] = true
+				}
+			}
+		}
+		input.Marker = result.Marker
+		if result.Marker == nil {
+			break
+		}
+	}
+	return rotations
+}
+
+// describeInstances lists the DB instances passing the server-side, tag, include and exclude filters
+func (e *RDSExporter) describeInstances(region string) ([]*rds.DBInstance, error) {
+	// The Resource Groups Tagging API filters the instances on their tags
+	var taggedInstances map[string]bool
+	if tagFilter != nil {
+		arns, err := tagFilter.ResourceARNs(e.sess, "rds:db")
+		if err != nil {
+			return nil, err
+		}
+		taggedInstances = arns
+	}
+
+	input := &rds.DescribeDBInstancesInput{
+		Filters: e.options.Filters,
+	}
 
 	// Get all DB instances.
 	// If a Marker is found, do pagination until last page
 	var instances []*rds.DBInstance
 	for {
-		exporterMetrics.IncrementRequests()
-		result, err := svc.DescribeDBInstances(input)
+		exporterMetrics.IncrementRequests(rds.ServiceName, "DescribeDBInstances")
+		result, err := e.svc.DescribeDBInstances(input)
 		if err != nil {
-			level.Error(e.logger).Log("msg", "Call to DescribeDBInstances failed", "region", *e.sess.Config.Region, "err", err)
-			exporterMetrics.IncrementErrors()
-			return
+			exporterMetrics.IncrementErrors(rds.ServiceName, "DescribeDBInstances", err)
+			return nil, err
+		}
+		for _, instance := range result.DBInstances {
+			if taggedInstances != nil && !taggedInstances[aws.StringValue(instance.DBInstanceArn)] {
+				continue
+			}
+			if e.includeInstance(*instance.DBInstanceIdentifier, aws.StringValue(instance.Engine)) {
+				instances = append(instances, instance)
+			}
 		}
-		instances = append(instances, result.DBInstances...)
 		input.Marker = result.Marker
 		if result.Marker == nil {
 			break
 		}
 	}
+	return instances, nil
+}
+
+// includeInstance returns true if the DB instance passes the engine, include and exclude filters
+func (e *RDSExporter) includeInstance(identifier, engine string) bool {
+	if len(e.options.Engines) > 0 && !e.options.Engines[engine] {
+		return false
+	}
+	if e.options.Exclude != nil && e.options.Exclude.MatchString(identifier) {
+		return false
+	}
+	return e.options.Include == nil || e.options.Include.MatchString(identifier)
+}
+
+// deprecatedEngineVersion returns true if the engine version starts with one of the deprecated versions of the engine
+func (e *RDSExporter) deprecatedEngineVersion(engine, version string) bool {
+	for _, prefix := range e.options.DeprecatedEngineVersions[engine] {
+		if strings.HasPrefix(version, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// boolMetric returns the metric of a boolean, either as 0 or 1 or, with BooleanLabels, as a value label
+func (e *RDSExporter) boolMetric(desc *prometheus.Desc, value bool, labels ...string) prometheus.Metric {
+	if e.options.BooleanLabels {
+		return prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, 1, append(labels, strconv.FormatBool(value))...)
+	}
+	if value {
+		return prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, 1, labels...)
+	}
+	return prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, 0, labels...)
+}
+
+// collectInstance collects the metrics of a single DB instance, it is called concurrently by the Collect workers.
+// The attributes can be missing, for example while the instance is being created, the metrics of a missing attribute are skipped.
+func (e *RDSExporter) collectInstance(ch chan<- prometheus.Metric, region string, instance *rds.DBInstance) {
+	identifier := aws.StringValue(instance.DBInstanceIdentifier)
+	class := aws.StringValue(instance.DBInstanceClass)
+	var parameterGroup string
+	if len(instance.DBParameterGroups) > 0 {
+		parameterGroup = aws.StringValue(instance.DBParameterGroups[0].DBParameterGroupName)
+	}
+
+	var maxConnections int64
+	if valmap, ok := DBMaxConnections[class]; ok {
+		var maxconn int64
+		var found bool
+		if val, ok := valmap[parameterGroup]; ok {
+			maxconn = val
+			found = true
+		} else if val, ok := valmap["default"]; ok {
+			maxconn = val
+			found = true
+		}
+		if found {
+			level.Debug(e.logger).Log("msg", "Found mapping for instance",
+				"type", class,
+				"group", parameterGroup,
+				"value", maxconn)
+			maxConnections = maxconn
+			ch <- e.boolMetric(e.MaxConnectionsMappingError, false, region, identifier, class)
+		} else {
+			level.Error(e.logger).Log("msg", "No DB max_connections mapping exists for instance",
+				"type", class,
+				"group", parameterGroup)
+			ch <- e.boolMetric(e.MaxConnectionsMappingError, true, region, identifier, class)
+		}
+	} else {
+		level.Error(e.logger).Log("msg", "No DB max_connections mapping exists for instance",
+			"type", class)
+		ch <- e.boolMetric(e.MaxConnectionsMappingError, true, region, identifier, class)
+	}
+
+	if instance.PubliclyAccessible != nil {
+		ch <- e.boolMetric(e.PubliclyAccessible, *instance.PubliclyAccessible, region, identifier)
+	}
+
+	if instance.StorageEncrypted != nil {
+		ch <- e.boolMetric(e.StorageEncrypted, *instance.StorageEncrypted, region, identifier)
+	}
+
+	if len(e.options.TeamTagKeys) > 0 {
+		e.collectTeam(ch, region, instance)
+	}
+
+	if instance.MultiAZ != nil {
+		ch <- e.boolMetric(e.MultiAZ, *instance.MultiAZ, region, identifier)
+	}
+
+	// The secondary availability zone is only set for Multi-AZ instances
+	ch <- prometheus.MustNewConstMetric(e.InstanceInfo, prometheus.GaugeValue, 1, region, identifier, aws.StringValue(instance.AvailabilityZone), aws.StringValue(instance.SecondaryAvailabilityZone))
+
+	// Iops is only set for storage types supporting provisioned IOPS
+	if instance.Iops != nil {
+		ch <- prometheus.MustNewConstMetric(e.Iops, prometheus.GaugeValue, float64(*instance.Iops), region, identifier)
+	}
+
+	if aws.StringValue(instance.StorageType) == "gp3" && instance.AllocatedStorage != nil {
+		aboveBaseline := aws.Int64Value(instance.Iops) > gp3BaselineIops || aws.Int64Value(instance.StorageThroughput) > gp3BaselineThroughput
+		capped := *instance.AllocatedStorage < gp3BaselineStorageThreshold && aboveBaseline
+		ch <- e.boolMetric(e.GP3BaselineCapped, capped, region, identifier)
+	}
+
+	if instance.ReadReplicaSourceDBInstanceIdentifier != nil {
+		ch <- prometheus.MustNewConstMetric(e.ReadReplicaInfo, prometheus.GaugeValue, 1, region, identifier, *instance.ReadReplicaSourceDBInstanceIdentifier)
+	} else {
+		ch <- prometheus.MustNewConstMetric(e.ReadReplicaCount, prometheus.GaugeValue, float64(len(instance.ReadReplicaDBInstanceIdentifiers)), region, identifier)
+	}
+
+	ch <- prometheus.MustNewConstMetric(e.MaxConnections, prometheus.GaugeValue, float64(maxConnections), region, identifier)
+	if instance.AllocatedStorage != nil {
+		ch <- prometheus.MustNewConstMetric(e.AllocatedStorage, prometheus.GaugeValue, float64(*instance.AllocatedStorage*1024*1024*1024), region, identifier)
+	}
+	if instance.DBInstanceStatus != nil {
+		ch <- prometheus.MustNewConstMetric(e.DBInstanceStatus, prometheus.GaugeValue, 1, region, identifier, *instance.DBInstanceStatus)
+	}
+	engine := aws.StringValue(instance.Engine)
+	engineVersion := aws.StringValue(instance.EngineVersion)
+	ch <- prometheus.MustNewConstMetric(e.EngineVersion, prometheus.GaugeValue, 1, region, identifier, engine, engineVersion)
+	ch <- e.boolMetric(e.EngineVersionDeprecated, e.deprecatedEngineVersion(engine, engineVersion), region, identifier, engine, engineVersion)
+	ch <- prometheus.MustNewConstMetric(e.DBInstanceClass, prometheus.GaugeValue, 1, region, identifier, class)
+	if instance.BackupRetentionPeriod != nil {
+		ch <- prometheus.MustNewConstMetric(e.BackupRetentionPeriod, prometheus.GaugeValue, float64(*instance.BackupRetentionPeriod), region, identifier)
+	}
+	if instance.StorageType != nil {
+		ch <- prometheus.MustNewConstMetric(e.StorageType, prometheus.GaugeValue, 1, region, identifier, *instance.StorageType)
+	}
+	// MaxAllocatedStorage is only set when storage autoscaling is enabled
+	if instance.MaxAllocatedStorage != nil {
+		maxAllocated := float64(*instance.MaxAllocatedStorage * 1024 * 1024 * 1024)
+		ch <- e.boolMetric(e.StorageAutoscalingEnabled, true, region, identifier)
+		ch <- prometheus.MustNewConstMetric(e.MaxAllocatedStorage, prometheus.GaugeValue, maxAllocated, region, identifier)
+		if maxAllocated > 0 && instance.AllocatedStorage != nil {
+			ch <- prometheus.MustNewConstMetric(e.MaxAllocatedStorageRatio, prometheus.GaugeValue, float64(*instance.AllocatedStorage*1024*1024*1024)/maxAllocated, region, identifier)
+		}
+	} else {
+		ch <- e.boolMetric(e.StorageAutoscalingEnabled, false, region, identifier)
+	}
+	if instance.DeletionProtection != nil {
+		ch <- e.boolMetric(e.DeletionProtection, *instance.DeletionProtection, region, identifier)
+	}
+	if instance.CopyTagsToSnapshot != nil {
+		ch <- e.boolMetric(e.CopyTagsToSnapshot, *instance.CopyTagsToSnapshot, region, identifier)
+	}
+	// Performance Insights fields are not set for the engines that don't support it
+	if instance.PerformanceInsightsEnabled != nil {
+		ch <- e.boolMetric(e.PerformanceInsightsEnabled, *instance.PerformanceInsightsEnabled, region, identifier)
+	}
+	if instance.PerformanceInsightsRetentionPeriod != nil {
+		ch <- prometheus.MustNewConstMetric(e.PerformanceInsightsRetention, prometheus.GaugeValue, float64(*instance.PerformanceInsightsRetentionPeriod), region, identifier)
+	}
+	for _, parameterGroup := range instance.DBParameterGroups {
+		ch <- prometheus.MustNewConstMetric(e.ParameterApplyStatus, prometheus.GaugeValue, 1, region, identifier, aws.StringValue(parameterGroup.DBParameterGroupName), aws.StringValue(parameterGroup.ParameterApplyStatus))
+	}
+	for _, membership := range instance.OptionGroupMemberships {
+		optionGroupName := aws.StringValue(membership.OptionGroupName)
+		ch <- prometheus.MustNewConstMetric(e.OptionGroup, prometheus.GaugeValue, 1, region, identifier, optionGroupName)
+		ch <- e.boolMetric(e.OptionGroupPending, aws.StringValue(membership.Status) == "pending-apply", region, identifier, optionGroupName)
+	}
+}
+
+// collectTimes collects the time metrics of the DB instance, they keep changing while the instance itself doesn't
+// and are emitted for every instance, whether it changed or not
+func (e *RDSExporter) collectTimes(ch chan<- prometheus.Metric, region string, instance *rds.DBInstance) {
+	identifier := aws.StringValue(instance.DBInstanceIdentifier)
+	// LatestRestorableTime is not set until the first backup completes
+	if instance.LatestRestorableTime != nil {
+		ch <- prometheus.MustNewConstMetric(e.LatestRestorableTime, prometheus.CounterValue, float64(instance.LatestRestorableTime.Unix()), region, identifier)
+	}
+	// InstanceCreateTime is not set until the instance creation completes
+	if instance.InstanceCreateTime != nil {
+		ch <- prometheus.MustNewConstMetric(e.InstanceAge, prometheus.GaugeValue, time.Since(*instance.InstanceCreateTime).Seconds(), region, identifier)
+	}
+}
 
+// collectTeam emits the team owning the instance, looked up from the first configured team tag key found on it.
+// Tag keys are matched case-insensitively and values are lowercased and trimmed so that inconsistent tagging maps to a single team.
+func (e *RDSExporter) collectTeam(ch chan<- prometheus.Metric, region string, instance *rds.DBInstance) {
+	exporterMetrics.IncrementRequests(rds.ServiceName, "ListTagsForResource")
+	result, err := e.svc.ListTagsForResource(&rds.ListTagsForResourceInput{ResourceName: instance.DBInstanceArn})
+	if err != nil {
+		level.Error(e.logger).Log("msg", "Call to ListTagsForResource failed", "region", region, "instance", aws.StringValue(instance.DBInstanceIdentifier), "err", err)
+		exporterMetrics.IncrementErrors(rds.ServiceName, "ListTagsForResource", err)
+		ch <- prometheus.MustNewConstMetric(e.InstanceScrapeError, prometheus.GaugeValue, 1, region, aws.StringValue(instance.DBInstanceIdentifier), rdsScrapeStageTags)
+		return
+	}
+
+	for _, key := range e.options.TeamTagKeys {
+		for _, tag := range result.TagList {
+			if !strings.EqualFold(strings.TrimSpace(aws.StringValue(tag.Key)), strings.TrimSpace(key)) {
+				continue
+			}
+			team := strings.ToLower(strings.TrimSpace(aws.StringValue(tag.Value)))
+			if team == "" {
+				continue
+			}
+			ch <- prometheus.MustNewConstMetric(e.InstanceTeamInfo, prometheus.GaugeValue, 1, region, aws.StringValue(instance.DBInstanceIdentifier), team)
+			return
+		}
+	}
+}
+
+// updateInstanceHashes stores the hash of every instance and returns the identifiers of the instances
+// whose hash is the same as on the previous scrape. Instances that disappeared are forgotten.
+// Only the attributes exported by collectInstance are hashed, the other ones such as LatestRestorableTime
+// change on every scrape and would make every instance look changed.
+func (e *RDSExporter) updateInstanceHashes(instances []*rds.DBInstance) map[string]bool {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	unchanged := map[string]bool{}
+	hashes := make(map[string]uint64, len(instances))
 	for _, instance := range instances {
-		var maxConnections int64
-		if valmap, ok := DBMaxConnections[*instance.DBInstanceClass]; ok {
-			var maxconn int64
-			var found bool
-			if val, ok := valmap[*instance.DBParameterGroups[0].DBParameterGroupName]; ok {
-				maxconn = val
-				found = true
-			} else if val, ok := valmap["default"]; ok {
-				maxconn = val
-				found = true
+		h := fnv.New64a()
+		h.Write([]byte(rdsHashedAttributes(instance).String()))
+		sum := h.Sum64()
+
+		if previous, ok := e.instanceHashes[*instance.DBInstanceIdentifier]; ok && previous == sum {
+			unchanged[*instance.DBInstanceIdentifier] = true
+		}
+		hashes[*instance.DBInstanceIdentifier] = sum
+	}
+	e.instanceHashes = hashes
+	return unchanged
+}
+
+// rdsHashedAttributes returns a copy of the DB instance with only the attributes exported by collectInstance
+func rdsHashedAttributes(instance *rds.DBInstance) *rds.DBInstance {
+	return &rds.DBInstance{
+		AllocatedStorage:                      instance.AllocatedStorage,
+		AvailabilityZone:                      instance.AvailabilityZone,
+		BackupRetentionPeriod:                 instance.BackupRetentionPeriod,
+		CopyTagsToSnapshot:                    instance.CopyTagsToSnapshot,
+		DBInstanceArn:                         instance.DBInstanceArn,
+		DBInstanceClass:                       instance.DBInstanceClass,
+		DBInstanceIdentifier:                  instance.DBInstanceIdentifier,
+		DBInstanceStatus:                      instance.DBInstanceStatus,
+		DBParameterGroups:                     instance.DBParameterGroups,
+		DeletionProtection:                    instance.DeletionProtection,
+		Engine:                                instance.Engine,
+		EngineVersion:                         instance.EngineVersion,
+		Iops:                                  instance.Iops,
+		MaxAllocatedStorage:                   instance.MaxAllocatedStorage,
+		MultiAZ:                               instance.MultiAZ,
+		OptionGroupMemberships:                instance.OptionGroupMemberships,
+		PerformanceInsightsEnabled:            instance.PerformanceInsightsEnabled,
+		PerformanceInsightsRetentionPeriod:    instance.PerformanceInsightsRetentionPeriod,
+		PubliclyAccessible:                    instance.PubliclyAccessible,
+		ReadReplicaDBInstanceIdentifiers:      instance.ReadReplicaDBInstanceIdentifiers,
+		ReadReplicaSourceDBInstanceIdentifier: instance.ReadReplicaSourceDBInstanceIdentifier,
+		SecondaryAvailabilityZone:             instance.SecondaryAvailabilityZone,
+		StorageEncrypted:                      instance.StorageEncrypted,
+		StorageType:                           instance.StorageType,
+	}
+}
+
+// collectSnapshots collects the metrics of all the DB snapshots
+func (e *RDSExporter) collectSnapshots(ch chan<- prometheus.Metric, region string) {
+	input := &rds.DescribeDBSnapshotsInput{}
+
+	// Get all DB snapshots.
+	// If a Marker is found, do pagination until last page
+	var snapshots []*rds.DBSnapshot
+	for {
+		exporterMetrics.IncrementRequests(rds.ServiceName, "DescribeDBSnapshots")
+		result, err := e.svc.DescribeDBSnapshots(input)
+		if err != nil {
+			level.Error(e.logger).Log("msg", "Call to DescribeDBSnapshots failed", "region", region, "err", err)
+			exporterMetrics.IncrementErrors(rds.ServiceName, "DescribeDBSnapshots", err)
+			return
+		}
+		snapshots = append(snapshots, result.DBSnapshots...)
+		input.Marker = result.Marker
+		if result.Marker == nil {
+			break
+		}
+	}
+
+	type snapshotGroup struct {
+		instance     string
+		snapshotType string
+	}
+	counts := map[snapshotGroup]float64{}
+	oldest := map[snapshotGroup]time.Time{}
+
+	for _, snapshot := range snapshots {
+		if !e.includeInstance(aws.StringValue(snapshot.DBInstanceIdentifier), aws.StringValue(snapshot.Engine)) {
+			continue
+		}
+		if snapshot.PercentProgress != nil {
+			ch <- prometheus.MustNewConstMetric(e.SnapshotProgress, prometheus.GaugeValue, float64(*snapshot.PercentProgress), region, aws.StringValue(snapshot.DBInstanceIdentifier), *snapshot.DBSnapshotIdentifier)
+		}
+
+		group := snapshotGroup{aws.StringValue(snapshot.DBInstanceIdentifier), aws.StringValue(snapshot.SnapshotType)}
+		counts[group]++
+		// SnapshotCreateTime is not set until the snapshot creation starts
+		if snapshot.SnapshotCreateTime != nil {
+			if t, ok := oldest[group]; !ok || snapshot.SnapshotCreateTime.Before(t) {
+				oldest[group] = *snapshot.SnapshotCreateTime
 			}
-			if found {
-				level.Debug(e.logger).Log("msg", "Found mapping for instance",
-					"type", *instance.DBInstanceClass,
-					"group", *instance.DBParameterGroups[0].DBParameterGroupName,
-					"value", maxconn)
-				maxConnections = maxconn
-				ch <- prometheus.MustNewConstMetric(e.MaxConnectionsMappingError, prometheus.GaugeValue, 0, *e.sess.Config.Region, *instance.DBInstanceIdentifier, *instance.DBInstanceClass)
-			} else {
-				level.Error(e.logger).Log("msg", "No DB max_connections mapping exists for instance",
-					"type", *instance.DBInstanceClass,
-					"group", *instance.DBParameterGroups[0].DBParameterGroupName)
-				ch <- prometheus.MustNewConstMetric(e.MaxConnectionsMappingError, prometheus.GaugeValue, 1, *e.sess.Config.Region, *instance.DBInstanceIdentifier, *instance.DBInstanceClass)
+		}
+	}
+
+	for group, count := range counts {
+		ch <- prometheus.MustNewConstMetric(e.SnapshotCount, prometheus.GaugeValue, count, region, group.instance, group.snapshotType)
+	}
+	for group, t := range oldest {
+		ch <- prometheus.MustNewConstMetric(e.OldestSnapshotAge, prometheus.GaugeValue, time.Since(t).Seconds(), region, group.instance, group.snapshotType)
+	}
+}
+
+// collectFreeStorageSpace collects the free storage space of the DB instances from CloudWatch
+// along with the ratio of their allocated storage in use
+func (e *RDSExporter) collectFreeStorageSpace(ch chan<- prometheus.Metric, region string, instances []*rds.DBInstance) {
+	period := int64(e.options.CloudWatchPeriod.Seconds())
+	if period < 60 {
+		period = 60
+	}
+	now := time.Now()
+
+	// GetMetricData accepts at most metricDataQueriesLimit queries per call
+	for start := 0; start < len(instances); start += metricDataQueriesLimit {
+		end := start + metricDataQueriesLimit
+		if end > len(instances) {
+			end = len(instances)
+		}
+		batch := instances[start:end]
+
+		queries := make([]*cloudwatch.MetricDataQuery, 0, len(batch))
+		for i, instance := range batch {
+			queries = append(queries, &cloudwatch.MetricDataQuery{
+				Id: aws.String(fmt.Sprintf("i%d", i)),
+				MetricStat: &cloudwatch.MetricStat{
+					Metric: &cloudwatch.Metric{
+						Namespace:  aws.String("AWS/RDS"),
+						MetricName: aws.String("FreeStorageSpace"),
+						Dimensions: []*cloudwatch.Dimension{{
+							Name:  aws.String("DBInstanceIdentifier"),
+							Value: instance.DBInstanceIdentifier,
+						}},
+					},
+					Period: aws.Int64(period),
+					Stat:   aws.String(cloudwatch.StatisticAverage),
+				},
+			})
+		}
+		input := &cloudwatch.GetMetricDataInput{
+			MetricDataQueries: queries,
+			// Look back over a few periods as the latest datapoint can be published late
+			StartTime: aws.Time(now.Add(-3 * time.Duration(period) * time.Second)),
+			EndTime:   aws.Time(now),
+			ScanBy:    aws.String(cloudwatch.ScanByTimestampDescending),
+		}
+
+		// Get the latest datapoint of every query.
+		// If a NextToken is found, do pagination until last page
+		latest := map[string]float64{}
+		for {
+			exporterMetrics.IncrementRequests(cloudwatch.ServiceName, "GetMetricData")
+			result, err := e.cwSvc.GetMetricData(input)
+			if err != nil {
+				level.Error(e.logger).Log("msg", "Call to GetMetricData failed", "region", region, "err", err)
+				exporterMetrics.IncrementErrors(cloudwatch.ServiceName, "GetMetricData", err)
+				return
+			}
+			for _, data := range result.MetricDataResults {
+				if _, ok := latest[*data.Id]; !ok && len(data.Values) > 0 {
+					latest[*data.Id] = *data.Values[0]
+				}
+			}
+			input.NextToken = result.NextToken
+			if result.NextToken == nil {
+				break
 			}
-		} else {
-			level.Error(e.logger).Log("msg", "No DB max_connections mapping exists for instance",
-				"type", *instance.DBInstanceClass)
-			ch <- prometheus.MustNewConstMetric(e.MaxConnectionsMappingError, prometheus.GaugeValue, 1, *e.sess.Config.Region, *instance.DBInstanceIdentifier, *instance.DBInstanceClass)
 		}
 
-		if *instance.PubliclyAccessible {
-			ch <- prometheus.MustNewConstMetric(e.PubliclyAccessible, prometheus.GaugeValue, 1, *e.sess.Config.Region, *instance.DBInstanceIdentifier)
+		for i, instance := range batch {
+			free, ok := latest[fmt.Sprintf("i%d", i)]
+			if !ok {
+				continue
+			}
+			ch <- prometheus.MustNewConstMetric(e.FreeStorageSpace, prometheus.GaugeValue, free, region, *instance.DBInstanceIdentifier)
+			if allocated := float64(aws.Int64Value(instance.AllocatedStorage) * 1024 * 1024 * 1024); allocated > 0 {
+				ch <- prometheus.MustNewConstMetric(e.StorageUsedRatio, prometheus.GaugeValue, (allocated-free)/allocated, region, *instance.DBInstanceIdentifier)
+			}
+		}
+	}
+}
 
-		} else {
-			ch <- prometheus.MustNewConstMetric(e.PubliclyAccessible, prometheus.GaugeValue, 0, *e.sess.Config.Region, *instance.DBInstanceIdentifier)
+// collectClusters collects the metrics of all the DB clusters
+func (e *RDSExporter) collectClusters(ch chan<- prometheus.Metric, region string) {
+	input := &rds.DescribeDBClustersInput{}
 
+	// Get all DB clusters.
+	// If a Marker is found, do pagination until last page
+	var clusters []*rds.DBCluster
+	for {
+		exporterMetrics.IncrementRequests(rds.ServiceName, "DescribeDBClusters")
+		result, err := e.svc.DescribeDBClusters(input)
+		if err != nil {
+			level.Error(e.logger).Log("msg", "Call to DescribeDBClusters failed", "region", region, "err", err)
+			exporterMetrics.IncrementErrors(rds.ServiceName, "DescribeDBClusters", err)
+			return
 		}
+		clusters = append(clusters, result.DBClusters...)
+		input.Marker = result.Marker
+		if result.Marker == nil {
+			break
+		}
+	}
 
-		if *instance.StorageEncrypted {
-			ch <- prometheus.MustNewConstMetric(e.StorageEncrypted, prometheus.GaugeValue, 1, *e.sess.Config.Region, *instance.DBInstanceIdentifier)
+	for _, cluster := range clusters {
+		ch <- prometheus.MustNewConstMetric(e.ClusterInfo, prometheus.GaugeValue, 1, region, *cluster.DBClusterIdentifier, aws.StringValue(cluster.Engine), aws.StringValue(cluster.EngineVersion), aws.StringValue(cluster.Status))
+		ch <- prometheus.MustNewConstMetric(e.ClusterMemberCount, prometheus.GaugeValue, float64(len(cluster.DBClusterMembers)), region, *cluster.DBClusterIdentifier)
+		ch <- prometheus.MustNewConstMetric(e.ClusterBacktrackWindow, prometheus.GaugeValue, float64(aws.Int64Value(cluster.BacktrackWindow)), region, *cluster.DBClusterIdentifier)
+		// A cluster without members, such as one whose instances are being created, has no writer yet
+		for _, member := range cluster.DBClusterMembers {
+			ch <- e.boolMetric(e.ClusterMemberIsWriter, aws.BoolValue(member.IsClusterWriter), region, *cluster.DBClusterIdentifier, aws.StringValue(member.DBInstanceIdentifier))
+		}
+	}
+}
 
-		} else {
-			ch <- prometheus.MustNewConstMetric(e.StorageEncrypted, prometheus.GaugeValue, 0, *e.sess.Config.Region, *instance.DBInstanceIdentifier)
+// collectReservedInstances collects the count and normalized units of the active reserved DB instances per instance family,
+// the count of the reserved DB instances per instance class and state and the expiry of every reservation
+func (e *RDSExporter) collectReservedInstances(ch chan<- prometheus.Metric, region string) {
+	input := &rds.DescribeReservedDBInstancesInput{}
 
+	// Get all reserved DB instances.
+	// If a Marker is found, do pagination until last page
+	var reservations []*rds.ReservedDBInstance
+	for {
+		exporterMetrics.IncrementRequests(rds.ServiceName, "DescribeReservedDBInstances")
+		result, err := e.svc.DescribeReservedDBInstances(input)
+		if err != nil {
+			level.Error(e.logger).Log("msg", "Call to DescribeReservedDBInstances failed", "region", region, "err", err)
+			exporterMetrics.IncrementErrors(rds.ServiceName, "DescribeReservedDBInstances", err)
+			return
+		}
+		reservations = append(reservations, result.ReservedDBInstances...)
+		input.Marker = result.Marker
+		if result.Marker == nil {
+			break
 		}
+	}
 
-		ch <- prometheus.MustNewConstMetric(e.MaxConnections, prometheus.GaugeValue, float64(maxConnections), *e.sess.Config.Region, *instance.DBInstanceIdentifier)
-		ch <- prometheus.MustNewConstMetric(e.AllocatedStorage, prometheus.GaugeValue, float64(*instance.AllocatedStorage*1024*1024*1024), *e.sess.Config.Region, *instance.DBInstanceIdentifier)
-		ch <- prometheus.MustNewConstMetric(e.DBInstanceStatus, prometheus.GaugeValue, 1, *e.sess.Config.Region, *instance.DBInstanceIdentifier, *instance.DBInstanceStatus)
-		ch <- prometheus.MustNewConstMetric(e.EngineVersion, prometheus.GaugeValue, 1, *e.sess.Config.Region, *instance.DBInstanceIdentifier, *instance.Engine, *instance.EngineVersion)
-		ch <- prometheus.MustNewConstMetric(e.DBInstanceClass, prometheus.GaugeValue, 1, *e.sess.Config.Region, *instance.DBInstanceIdentifier, *instance.DBInstanceClass)
-		ch <- prometheus.MustNewConstMetric(e.LatestRestorableTime, prometheus.CounterValue, float64(instance.LatestRestorableTime.Unix()), *e.sess.Config.Region, *instance.DBInstanceIdentifier)
+	type classState struct {
+		class string
+		state string
+	}
+	classCounts := map[classState]float64{}
+	counts := map[string]float64{}
+	units := map[string]float64{}
+	for _, reservation := range reservations {
+		classCounts[classState{aws.StringValue(reservation.DBInstanceClass), aws.StringValue(reservation.State)}] += float64(aws.Int64Value(reservation.DBInstanceCount))
+		// The start time and duration are not set yet while the reservation is being purchased
+		if reservation.StartTime != nil && reservation.Duration != nil {
+			expiry := reservation.StartTime.Add(time.Duration(*reservation.Duration) * time.Second)
+			ch <- prometheus.MustNewConstMetric(e.ReservedInstanceExpiry, prometheus.GaugeValue, float64(expiry.Unix()), region, aws.StringValue(reservation.ReservedDBInstanceId), aws.StringValue(reservation.DBInstanceClass))
+		}
+
+		if aws.StringValue(reservation.State) != "active" {
+			continue
+		}
+		// Instance classes are formatted as db.<family>.<size>, e.g. db.m5.large
+		class := aws.StringValue(reservation.DBInstanceClass)
+		i := strings.LastIndex(class, ".")
+		if i == -1 {
+			continue
+		}
+		family, size := class[:i], class[i+1:]
+		count := float64(aws.Int64Value(reservation.DBInstanceCount))
+		counts[family] += count
+
+		factor, ok := RDSNormalizationFactors[size]
+		if !ok {
+			level.Debug(e.logger).Log("msg", "No normalization factor for reserved instance size", "class", class)
+			continue
+		}
+		// Multi-AZ reservations cover twice the units of a Single-AZ one
+		if aws.BoolValue(reservation.MultiAZ) {
+			factor *= 2
+		}
+		units[family] += count * factor
+	}
+
+	for family, count := range counts {
+		ch <- prometheus.MustNewConstMetric(e.ReservedInstanceFamilyCount, prometheus.GaugeValue, count, region, family)
+		ch <- prometheus.MustNewConstMetric(e.ReservedInstanceNormalizedUnits, prometheus.GaugeValue, units[family], region, family)
+	}
+	for key, count := range classCounts {
+		ch <- prometheus.MustNewConstMetric(e.ReservedInstanceCount, prometheus.GaugeValue, count, region, key.class, key.state)
 	}
 }
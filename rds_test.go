@@ -0,0 +1,583 @@
+package main
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/rds"
+	"github.com/aws/aws-sdk-go/service/rds/rdsiface"
+	"github.com/go-kit/kit/log"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const testRegion = "us-east-1"
+
+// fakeRDS returns canned DB instances split in pages, the calls it doesn't implement panic
+type fakeRDS struct {
+	rdsiface.RDSAPI
+
+	instancePages [][]*rds.DBInstance
+	instancesErr  error
+	tags          map[string][]*rds.Tag
+	tagErrors     map[string]error
+
+	describeCalls int
+	snapshotCalls int
+}
+
+// DescribeDBInstances returns the page whose index is the marker
+func (f *fakeRDS) DescribeDBInstances(input *rds.DescribeDBInstancesInput) (*rds.DescribeDBInstancesOutput, error) {
+	f.describeCalls++
+	if f.instancesErr != nil {
+		return nil, f.instancesErr
+	}
+	page := 0
+	if input.Marker != nil {
+		page, _ = strconv.Atoi(*input.Marker)
+	}
+	output := &rds.DescribeDBInstancesOutput{}
+	if page < len(f.instancePages) {
+		output.DBInstances = f.instancePages[page]
+	}
+	if page+1 < len(f.instancePages) {
+		output.Marker = aws.String(strconv.Itoa(page + 1))
+	}
+	return output, nil
+}
+
+func (f *fakeRDS) DescribeDBSnapshots(*rds.DescribeDBSnapshotsInput) (*rds.DescribeDBSnapshotsOutput, error) {
+	f.snapshotCalls++
+	return &rds.DescribeDBSnapshotsOutput{}, nil
+}
+
+func (f *fakeRDS) DescribeDBClusters(*rds.DescribeDBClustersInput) (*rds.DescribeDBClustersOutput, error) {
+	return &rds.DescribeDBClustersOutput{}, nil
+}
+
+func (f *fakeRDS) DescribeReservedDBInstances(*rds.DescribeReservedDBInstancesInput) (*rds.DescribeReservedDBInstancesOutput, error) {
+	return &rds.DescribeReservedDBInstancesOutput{}, nil
+}
+
+func (f *fakeRDS) DescribePendingMaintenanceActions(*rds.DescribePendingMaintenanceActionsInput) (*rds.DescribePendingMaintenanceActionsOutput, error) {
+	return &rds.DescribePendingMaintenanceActionsOutput{}, nil
+}
+
+// ListTagsForResource is only read by the workers, the maps are not modified during a collection
+func (f *fakeRDS) ListTagsForResource(input *rds.ListTagsForResourceInput) (*rds.ListTagsForResourceOutput, error) {
+	if err := f.tagErrors[aws.StringValue(input.ResourceName)]; err != nil {
+		return nil, err
+	}
+	return &rds.ListTagsForResourceOutput{TagList: f.tags[aws.StringValue(input.ResourceName)]}, nil
+}
+
+// testInstance returns a DB instance with every attribute the collector reads
+func testInstance(identifier, class, parameterGroup, engine string) *rds.DBInstance {
+	return &rds.DBInstance{
+		AllocatedStorage:      aws.Int64(100),
+		BackupRetentionPeriod: aws.Int64(7),
+		DBInstanceArn:         aws.String("arn:aws:rds:" + testRegion + ":123456789012:db:" + identifier),
+		DBInstanceClass:       aws.String(class),
+		DBInstanceIdentifier:  aws.String(identifier),
+		DBInstanceStatus:      aws.String("available"),
+		DBParameterGroups: []*rds.DBParameterGroupStatus{
+			{DBParameterGroupName: aws.String(parameterGroup), ParameterApplyStatus: aws.String("in-sync")},
+		},
+		Engine:               aws.String(engine),
+		EngineVersion:        aws.String("11.6"),
+		InstanceCreateTime:   aws.Time(time.Now().Add(-time.Hour)),
+		LatestRestorableTime: aws.Time(time.Now()),
+		MultiAZ:              aws.Bool(false),
+		PubliclyAccessible:   aws.Bool(false),
+		StorageEncrypted:     aws.Bool(true),
+		StorageType:          aws.String("gp2"),
+	}
+}
+
+// newTestRDSExporter returns an enabled RDS exporter in testRegion making its calls to svc
+func newTestRDSExporter(svc rdsiface.RDSAPI, namespace string, options RDSOptions) *RDSExporter {
+	options.Enabled = true
+	sess := session.Must(session.NewSession(aws.NewConfig().WithRegion(testRegion)))
+	return NewRDSExporter(sess, svc, nil, namespace, log.NewNopLogger(), options)
+}
+
+// rdsSample returns the key of the sample of an instance metric in collectSamples
+func rdsSample(name, identifier string) string {
+	return fmt.Sprintf("%s_%s{aws_region=%q,dbinstance_identifier=%q}", defaultNamespace, name, testRegion, identifier)
+}
+
+func TestRDSExporterPagination(t *testing.T) {
+	tests := []struct {
+		name          string
+		pages         [][]*rds.DBInstance
+		wantInstances []string
+		wantCalls     int
+	}{
+		{
+			name:      "no instances",
+			wantCalls: 1,
+		},
+		{
+			name:      "empty page",
+			pages:     [][]*rds.DBInstance{{}},
+			wantCalls: 1,
+		},
+		{
+			name: "single page",
+			pages: [][]*rds.DBInstance{
+				{testInstance("db1", "db.m5.large", "default.postgres11", "postgres"), testInstance("db2", "db.m5.large", "default.postgres11", "postgres")},
+			},
+			wantInstances: []string{"db1", "db2"},
+			wantCalls:     1,
+		},
+		{
+			name: "several pages",
+			pages: [][]*rds.DBInstance{
+				{testInstance("db1", "db.m5.large", "default.postgres11", "postgres")},
+				{testInstance("db2", "db.m5.large", "default.postgres11", "postgres"), testInstance("db3", "db.m5.large", "default.postgres11", "postgres")},
+				{testInstance("db4", "db.m5.large", "default.postgres11", "postgres")},
+			},
+			wantInstances: []string{"db1", "db2", "db3", "db4"},
+			wantCalls:     3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &fakeRDS{instancePages: tt.pages}
+			samples := collectSamples(t, newTestRDSExporter(svc, defaultNamespace, RDSOptions{}))
+
+			if svc.describeCalls != tt.wantCalls {
+				t.Errorf("DescribeDBInstances calls = %d, want %d", svc.describeCalls, tt.wantCalls)
+			}
+			total := fmt.Sprintf("%s_rds_instances_total{aws_region=%q}", defaultNamespace, testRegion)
+			if got := samples[total]; got != float64(len(tt.wantInstances)) {
+				t.Errorf("%s = %v, want %d", total, got, len(tt.wantInstances))
+			}
+			if got := countSamples(samples, defaultNamespace+"_rds_maxconnections"); got != len(tt.wantInstances) {
+				t.Errorf("got %d max connections samples, want %d", got, len(tt.wantInstances))
+			}
+			for _, identifier := range tt.wantInstances {
+				if _, ok := samples[rdsSample("rds_maxconnections", identifier)]; !ok {
+					t.Errorf("missing max connections of %s", identifier)
+				}
+			}
+		})
+	}
+}
+
+func TestRDSExporterMaxConnections(t *testing.T) {
+	// A class whose mapping has no default value
+	DBMaxConnections["db.test.large"] = map[string]int64{"custom": 10}
+	defer delete(DBMaxConnections, "db.test.large")
+
+	tests := []struct {
+		name               string
+		class              string
+		parameterGroup     string
+		wantMaxConnections float64
+		wantMappingError   float64
+	}{
+		{
+			name:               "parameter group mapping",
+			class:              "db.m5.large",
+			parameterGroup:     "default.postgres11",
+			wantMaxConnections: 823,
+		},
+		{
+			name:               "default mapping",
+			class:              "db.t2.small",
+			parameterGroup:     "custom-mysql",
+			wantMaxConnections: 150,
+		},
+		{
+			name:             "no parameter group nor default mapping",
+			class:            "db.test.large",
+			parameterGroup:   "other",
+			wantMappingError: 1,
+		},
+		{
+			name:             "unknown instance class",
+			class:            "db.r5.large",
+			parameterGroup:   "default.postgres11",
+			wantMappingError: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &fakeRDS{instancePages: [][]*rds.DBInstance{{testInstance("db1", tt.class, tt.parameterGroup, "postgres")}}}
+			samples := collectSamples(t, newTestRDSExporter(svc, defaultNamespace, RDSOptions{}))
+
+			maxConnections, ok := samples[rdsSample("rds_maxconnections", "db1")]
+			if !ok || maxConnections != tt.wantMaxConnections {
+				t.Errorf("max connections = %v (found %t), want %v", maxConnections, ok, tt.wantMaxConnections)
+			}
+			mappingError := fmt.Sprintf("%s_rds_maxconnections_error{aws_region=%q,dbinstance_identifier=%q,instance_class=%q}", defaultNamespace, testRegion, "db1", tt.class)
+			if got, ok := samples[mappingError]; !ok || got != tt.wantMappingError {
+				t.Errorf("mapping error = %v (found %t), want %v", got, ok, tt.wantMappingError)
+			}
+		})
+	}
+}
+
+func TestRDSExporterRegionUnavailable(t *testing.T) {
+	tests := []struct {
+		name          string
+		err           error
+		wantSnapshots int
+	}{
+		{
+			name: "opt-in required",
+			err:  awserr.New("OptInRequired", "The region is not enabled for the account", nil),
+		},
+		{
+			name:          "other error",
+			err:           awserr.New("Throttling", "Rate exceeded", nil),
+			wantSnapshots: 1,
+		},
+		{
+			name:          "available",
+			wantSnapshots: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &fakeRDS{instancesErr: tt.err}
+			collectSamples(t, newTestRDSExporter(svc, defaultNamespace, RDSOptions{}))
+
+			// The other calls of an unavailable region are skipped
+			if svc.snapshotCalls != tt.wantSnapshots {
+				t.Errorf("DescribeDBSnapshots calls = %d, want %d", svc.snapshotCalls, tt.wantSnapshots)
+			}
+		})
+	}
+}
+
+// collectedInstances returns the identifiers of the instances among candidates which have metrics in the samples
+func collectedInstances(samples map[string]float64, candidates []*rds.DBInstance) []string {
+	var identifiers []string
+	for _, instance := range candidates {
+		if _, ok := samples[rdsSample("rds_instance_heartbeat", *instance.DBInstanceIdentifier)]; ok {
+			identifiers = append(identifiers, *instance.DBInstanceIdentifier)
+		}
+	}
+	return identifiers
+}
+
+func TestRDSExporterIdentifierFilters(t *testing.T) {
+	instances := []*rds.DBInstance{
+		testInstance("prod-1", "db.m5.large", "default.postgres11", "postgres"),
+		testInstance("prod-2", "db.m5.large", "default.postgres11", "postgres"),
+		testInstance("test-1", "db.m5.large", "default.postgres11", "postgres"),
+	}
+	tags := map[string][]*rds.Tag{}
+	for _, instance := range instances {
+		tags[*instance.DBInstanceArn] = []*rds.Tag{{Key: aws.String("team"), Value: aws.String("dba")}}
+	}
+
+	tests := []struct {
+		name    string
+		include string
+		exclude string
+		want    []string
+	}{
+		{
+			name: "no filter",
+			want: []string{"prod-1", "prod-2", "test-1"},
+		},
+		{
+			name:    "include",
+			include: "^prod-",
+			want:    []string{"prod-1", "prod-2"},
+		},
+		{
+			name:    "exclude",
+			exclude: "^test-",
+			want:    []string{"prod-1", "prod-2"},
+		},
+		{
+			name:    "exclude takes precedence over include",
+			include: "^prod-",
+			exclude: "-2$",
+			want:    []string{"prod-1"},
+		},
+		{
+			name:    "nothing included",
+			include: "^staging-",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			options := RDSOptions{TeamTagKeys: []string{"team"}}
+			if tt.include != "" {
+				options.Include = regexp.MustCompile(tt.include)
+			}
+			if tt.exclude != "" {
+				options.Exclude = regexp.MustCompile(tt.exclude)
+			}
+			svc := &fakeRDS{instancePages: [][]*rds.DBInstance{instances}, tags: tags}
+			samples := collectSamples(t, newTestRDSExporter(svc, defaultNamespace, options))
+
+			if got := collectedInstances(samples, instances); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("collected instances = %v, want %v", got, tt.want)
+			}
+			// Only the collected instances are enriched with their tags
+			if got := countSamples(samples, defaultNamespace+"_rds_instance_team_info"); got != len(tt.want) {
+				t.Errorf("got %d team samples, want %d", got, len(tt.want))
+			}
+		})
+	}
+}
+
+func TestRDSExporterEngineFilter(t *testing.T) {
+	instances := []*rds.DBInstance{
+		testInstance("orders", "db.m5.large", "default.postgres11", "postgres"),
+		testInstance("orders-aurora", "db.m5.large", "default.aurora-postgresql11", "aurora-postgresql"),
+		testInstance("legacy", "db.t2.small", "default.mysql5.7", "mysql"),
+		testInstance("legacy-aurora", "db.t2.small", "default.aurora-mysql5.7", "aurora-mysql"),
+	}
+
+	tests := []struct {
+		name    string
+		engines string
+		include string
+		want    []string
+	}{
+		{
+			name: "all engines by default",
+			want: []string{"orders", "orders-aurora", "legacy", "legacy-aurora"},
+		},
+		{
+			name:    "postgres engines",
+			engines: "postgres,aurora-postgresql",
+			want:    []string{"orders", "orders-aurora"},
+		},
+		{
+			name:    "spaces around the engines",
+			engines: " mysql , ",
+			want:    []string{"legacy"},
+		},
+		{
+			name:    "combined with the identifier filter",
+			engines: "postgres,aurora-postgresql,mysql",
+			include: "-aurora$",
+			want:    []string{"orders-aurora"},
+		},
+		{
+			name:    "no instance running the engine",
+			engines: "sqlserver-ee",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			options := RDSOptions{Engines: rdsEngineSet(tt.engines)}
+			if tt.include != "" {
+				options.Include = regexp.MustCompile(tt.include)
+			}
+			svc := &fakeRDS{instancePages: [][]*rds.DBInstance{instances}}
+			samples := collectSamples(t, newTestRDSExporter(svc, defaultNamespace, options))
+
+			if got := collectedInstances(samples, instances); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("collected instances = %v, want %v", got, tt.want)
+			}
+			total := fmt.Sprintf("%s_rds_instances_total{aws_region=%q}", defaultNamespace, testRegion)
+			if got := samples[total]; got != float64(len(tt.want)) {
+				t.Errorf("%s = %v, want %d", total, got, len(tt.want))
+			}
+		})
+	}
+}
+
+func TestRDSExporterBooleanLabels(t *testing.T) {
+	publiclyAccessible := rdsSample("rds_publiclyaccessible", "db1")
+	storageEncrypted := rdsSample("rds_storageencrypted", "db1")
+	withValue := func(key, value string) string {
+		return strings.TrimSuffix(key, "}") + fmt.Sprintf(",value=%q}", value)
+	}
+
+	tests := []struct {
+		name          string
+		booleanLabels bool
+		want          map[string]float64
+	}{
+		{
+			name: "sample values",
+			want: map[string]float64{
+				publiclyAccessible: 0,
+				storageEncrypted:   1,
+			},
+		},
+		{
+			name:          "value labels",
+			booleanLabels: true,
+			want: map[string]float64{
+				withValue(publiclyAccessible, "false"): 1,
+				withValue(storageEncrypted, "true"):    1,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &fakeRDS{instancePages: [][]*rds.DBInstance{{testInstance("db1", "db.m5.large", "default.postgres11", "postgres")}}}
+			samples := collectSamples(t, newTestRDSExporter(svc, defaultNamespace, RDSOptions{BooleanLabels: tt.booleanLabels}))
+
+			for key, want := range tt.want {
+				if got, ok := samples[key]; !ok || got != want {
+					t.Errorf("%s = %v (found %t), want %v", key, got, ok, want)
+				}
+			}
+			// Each boolean has a single series in both styles
+			for _, name := range []string{"rds_publiclyaccessible", "rds_storageencrypted"} {
+				if got := countSamples(samples, defaultNamespace+"_"+name); got != 1 {
+					t.Errorf("got %d samples of %s, want 1", got, name)
+				}
+			}
+		})
+	}
+}
+
+func TestRDSExporterGP3BaselineCapped(t *testing.T) {
+	tests := []struct {
+		name       string
+		storage    int64
+		iops       int64
+		throughput int64
+		want       float64
+	}{
+		{name: "baseline", storage: 100, iops: 3000, throughput: 125, want: 0},
+		{name: "IOPS above the baseline", storage: 100, iops: 12000, throughput: 125, want: 1},
+		{name: "throughput above the baseline", storage: 100, iops: 3000, throughput: 500, want: 1},
+		{name: "above the storage threshold", storage: 400, iops: 12000, throughput: 500, want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			instance := testInstance("db1", "db.m5.large", "default.postgres11", "postgres")
+			instance.StorageType = aws.String("gp3")
+			instance.AllocatedStorage = aws.Int64(tt.storage)
+			instance.Iops = aws.Int64(tt.iops)
+			instance.StorageThroughput = aws.Int64(tt.throughput)
+			svc := &fakeRDS{instancePages: [][]*rds.DBInstance{{instance}}}
+			samples := collectSamples(t, newTestRDSExporter(svc, defaultNamespace, RDSOptions{}))
+
+			key := rdsSample("rds_gp3_baseline_capped", "db1")
+			if got, ok := samples[key]; !ok || got != tt.want {
+				t.Errorf("%s = %v (found %t), want %v", key, got, ok, tt.want)
+			}
+		})
+	}
+}
+
+func TestRDSExporterNamespace(t *testing.T) {
+	const namespace = "custom"
+	svc := &fakeRDS{instancePages: [][]*rds.DBInstance{{testInstance("db1", "db.m5.large", "default.postgres11", "postgres")}}}
+	exporter := newTestRDSExporter(svc, namespace, RDSOptions{})
+
+	descs := make(chan *prometheus.Desc)
+	go func() {
+		exporter.Describe(descs)
+		close(descs)
+	}()
+	for desc := range descs {
+		if !strings.Contains(desc.String(), `fqName: "`+namespace+`_rds_`) {
+			t.Errorf("descriptor %s is not in the %s namespace", desc, namespace)
+		}
+	}
+
+	samples := collectSamples(t, exporter)
+	for _, name := range []string{"custom_rds_maxconnections", "custom_rds_allocatedstorage", "custom_rds_instance_heartbeat"} {
+		if got := countSamples(samples, name); got != 1 {
+			t.Errorf("got %d samples of %s, want 1", got, name)
+		}
+	}
+	if got := countSamples(samples, defaultNamespace+"_rds_maxconnections"); got != 0 {
+		t.Errorf("got %d samples in the default namespace, want 0", got)
+	}
+}
+
+func TestRDSExporterInstanceErrors(t *testing.T) {
+	healthy := testInstance("healthy", "db.m5.large", "default.postgres11", "postgres")
+	untagged := testInstance("untagged", "db.m5.large", "default.postgres11", "postgres")
+	// An instance being created has no parameter group, storage type, backup retention nor restorable time yet
+	creating := testInstance("creating", "db.m5.large", "default.postgres11", "postgres")
+	creating.DBParameterGroups = nil
+	creating.StorageType = nil
+	creating.BackupRetentionPeriod = nil
+	creating.LatestRestorableTime = nil
+	creating.PubliclyAccessible = nil
+
+	team := []*rds.Tag{{Key: aws.String("team"), Value: aws.String("dba")}}
+	svc := &fakeRDS{
+		instancePages: [][]*rds.DBInstance{{healthy, untagged, creating}},
+		tags: map[string][]*rds.Tag{
+			*healthy.DBInstanceArn:  team,
+			*creating.DBInstanceArn: team,
+		},
+		tagErrors: map[string]error{
+			*untagged.DBInstanceArn: awserr.New("Throttling", "Rate exceeded", nil),
+		},
+	}
+	samples := collectSamples(t, newTestRDSExporter(svc, defaultNamespace, RDSOptions{TeamTagKeys: []string{"team"}}))
+
+	scrapeError := fmt.Sprintf("%s_rds_instance_scrape_error{aws_region=%q,dbinstance_identifier=%q,stage=%q}", defaultNamespace, testRegion, "untagged", rdsScrapeStageTags)
+	if got := samples[scrapeError]; got != 1 {
+		t.Errorf("%s = %v, want 1", scrapeError, got)
+	}
+	if got := countSamples(samples, defaultNamespace+"_rds_instance_scrape_error"); got != 1 {
+		t.Errorf("got %d scrape errors, want 1", got)
+	}
+	for _, identifier := range []string{"healthy", "creating"} {
+		team := fmt.Sprintf("%s_rds_instance_team_info{aws_region=%q,dbinstance_identifier=%q,team=%q}", defaultNamespace, testRegion, identifier, "dba")
+		if _, ok := samples[team]; !ok {
+			t.Errorf("missing team of %s", identifier)
+		}
+	}
+
+	// The instance whose tags could not be listed keeps its other metrics
+	for _, name := range []string{"rds_maxconnections", "rds_allocatedstorage", "rds_storageencrypted", "rds_backup_retention_period_days"} {
+		if _, ok := samples[rdsSample(name, "untagged")]; !ok {
+			t.Errorf("missing %s of the instance whose tags could not be listed", name)
+		}
+	}
+
+	// The instance being created only loses the metrics of its missing attributes
+	for _, name := range []string{"rds_maxconnections", "rds_allocatedstorage", "rds_storageencrypted", "rds_instance_age_seconds"} {
+		if _, ok := samples[rdsSample(name, "creating")]; !ok {
+			t.Errorf("missing %s of the instance being created", name)
+		}
+	}
+	for _, name := range []string{"rds_publiclyaccessible", "rds_backup_retention_period_days", "rds_latestrestorabletime"} {
+		if _, ok := samples[rdsSample(name, "creating")]; ok {
+			t.Errorf("unexpected %s of the instance being created", name)
+		}
+	}
+}
+
+func TestRDSExporterChangedOnly(t *testing.T) {
+	instance := testInstance("db1", "db.m5.large", "default.postgres11", "postgres")
+	svc := &fakeRDS{instancePages: [][]*rds.DBInstance{{instance}}}
+	exporter := newTestRDSExporter(svc, defaultNamespace, RDSOptions{ChangedOnly: true})
+	collectSamples(t, exporter)
+
+	// A new backup only moves the latest restorable time, the instance itself didn't change
+	instance.LatestRestorableTime = aws.Time(instance.LatestRestorableTime.Add(5 * time.Minute))
+	samples := collectSamples(t, exporter)
+	if got := countSamples(samples, defaultNamespace+"_rds_storageencrypted"); got != 0 {
+		t.Errorf("got %d rds_storageencrypted samples of an unchanged instance, want 0", got)
+	}
+	restorable := rdsSample("rds_latestrestorabletime", "db1")
+	if got, want := samples[restorable], float64(instance.LatestRestorableTime.Unix()); got != want {
+		t.Errorf("%s = %v, want %v", restorable, got, want)
+	}
+
+	instance.StorageEncrypted = aws.Bool(false)
+	samples = collectSamples(t, exporter)
+	if got := countSamples(samples, defaultNamespace+"_rds_storageencrypted"); got != 1 {
+		t.Errorf("got %d rds_storageencrypted samples of a changed instance, want 1", got)
+	}
+}
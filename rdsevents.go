@@ -0,0 +1,111 @@
+package main
+
+import (
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/rds"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// RDSEventsExporter defines an instance of the RDS events Exporter
+type RDSEventsExporter struct {
+	sess     *session.Session
+	lookback time.Duration
+	Events   *prometheus.Desc
+
+	enabled bool
+	logger  log.Logger
+}
+
+func init() {
+	RegisterCollector(func(sess *session.Session, namespace string, logger log.Logger) Collector {
+		return NewRDSEventsExporter(sess, namespace, logger, *rdsEventsEnabled, *rdsEventsLookback)
+	})
+}
+
+// NewRDSEventsExporter creates a new RDSEventsExporter instance counting the events of the last lookback period
+func NewRDSEventsExporter(sess *session.Session, namespace string, logger log.Logger, enabled bool, lookback time.Duration) *RDSEventsExporter {
+	return &RDSEventsExporter{
+		sess:     sess,
+		lookback: lookback,
+		enabled:  enabled,
+		Events: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "rds_events_total"),
+			"The number of RDS events of the DB instance during the lookback period, by event category.",
+			[]string{"aws_region", "dbinstance_identifier", "event_category"},
+			nil,
+		),
+		logger: logger,
+	}
+}
+
+// Name returns the name of the collector
+func (e *RDSEventsExporter) Name() string {
+	return "rdsevents"
+}
+
+// Enabled returns true if the collector has to be registered
+func (e *RDSEventsExporter) Enabled() bool {
+	return e.enabled
+}
+
+// Preflight checks the IAM permissions of the collector with a single call
+func (e *RDSEventsExporter) Preflight() (string, error) {
+	_, err := rds.New(e.sess).DescribeEvents(&rds.DescribeEventsInput{MaxRecords: aws.Int64(20)})
+	return "rds:DescribeEvents", err
+}
+
+// Describe is used by the Prometheus client to return a description of the metrics
+func (e *RDSEventsExporter) Describe(ch chan<- *prometheus.Desc) {
+	ch <- e.Events
+}
+
+// Collect is used by the Prometheus client to collect and return the metrics values
+func (e *RDSEventsExporter) Collect(ch chan<- prometheus.Metric) {
+	region := sessionRegion(e.sess)
+	svc := rds.New(e.sess)
+	input := &rds.DescribeEventsInput{
+		SourceType: aws.String(rds.SourceTypeDbInstance),
+		StartTime:  aws.Time(time.Now().Add(-e.lookback)),
+	}
+
+	// Get all events of the lookback period.
+	// If a Marker is found, do pagination until last page
+	var events []*rds.Event
+	for {
+		exporterMetrics.IncrementRequests(rds.ServiceName, "DescribeEvents")
+		result, err := svc.DescribeEvents(input)
+		if err != nil {
+			level.Error(e.logger).Log("msg", "Call to DescribeEvents failed", "region", region, "err", err)
+			exporterMetrics.IncrementErrors(rds.ServiceName, "DescribeEvents", err)
+			return
+		}
+		events = append(events, result.Events...)
+		input.Marker = result.Marker
+		if result.Marker == nil {
+			break
+		}
+	}
+
+	// An event can belong to several categories, it is counted in each of them
+	counts := map[string]map[string]int{}
+	for _, event := range events {
+		identifier := aws.StringValue(event.SourceIdentifier)
+		if _, ok := counts[identifier]; !ok {
+			counts[identifier] = map[string]int{}
+		}
+		for _, category := range event.EventCategories {
+			counts[identifier][aws.StringValue(category)]++
+		}
+	}
+
+	for identifier, categories := range counts {
+		for category, count := range categories {
+			ch <- prometheus.MustNewConstMetric(e.Events, prometheus.GaugeValue, float64(count), region, identifier, category)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"sync"
+)
+
+// readinessKey identifies a collector of an account, the account is empty when no role is assumed
+type readinessKey struct {
+	account   string
+	collector string
+}
+
+// Readiness keeps track of the collectors of every account that have successfully talked to AWS at least once
+type Readiness struct {
+	collectors map[readinessKey]bool
+
+	mutex *sync.Mutex
+}
+
+// NewReadiness creates a new readiness tracker
+func NewReadiness() *Readiness {
+	return &Readiness{
+		collectors: map[readinessKey]bool{},
+		mutex:      &sync.Mutex{},
+	}
+}
+
+// Register adds a collector of the account which has to complete a successful AWS call before the exporter is ready
+func (r *Readiness) Register(account, collector string) {
+	r.mutex.Lock()
+	key := readinessKey{account: account, collector: collector}
+	if _, ok := r.collectors[key]; !ok {
+		r.collectors[key] = false
+	}
+	r.mutex.Unlock()
+}
+
+// MarkReady records a successful AWS call for the given collector of the account
+func (r *Readiness) MarkReady(account, collector string) {
+	r.mutex.Lock()
+	r.collectors[readinessKey{account: account, collector: collector}] = true
+	r.mutex.Unlock()
+}
+
+// Ready returns true once every registered collector has completed a successful AWS call
+func (r *Readiness) Ready() bool {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	for _, ready := range r.collectors {
+		if !ready {
+			return false
+		}
+	}
+	return true
+}
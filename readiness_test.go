@@ -0,0 +1,18 @@
+package main
+
+import "testing"
+
+func TestReadinessAccounts(t *testing.T) {
+	r := NewReadiness()
+	r.Register("111111111111", "rds")
+	r.Register("222222222222", "rds")
+
+	r.MarkReady("111111111111", "rds")
+	if r.Ready() {
+		t.Error("Ready() = true with a collector of an account which never succeeded, want false")
+	}
+	r.MarkReady("222222222222", "rds")
+	if !r.Ready() {
+		t.Error("Ready() = false once the collector succeeded in every account, want true")
+	}
+}
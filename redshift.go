@@ -0,0 +1,152 @@
+package main
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/redshift"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// RedshiftExporter defines an instance of the Redshift Exporter
+type RedshiftExporter struct {
+	sess             *session.Session
+	ClusterEncrypted *prometheus.Desc
+	ClusterInfo      *prometheus.Desc
+	ClusterNodeCount *prometheus.Desc
+	ClusterStatus    *prometheus.Desc
+	ResourcesTotal   *prometheus.Desc
+
+	enabled bool
+	logger  log.Logger
+}
+
+func init() {
+	RegisterCollector(func(sess *session.Session, namespace string, logger log.Logger) Collector {
+		return NewRedshiftExporter(sess, namespace, logger, *redshiftEnabled)
+	})
+}
+
+// NewRedshiftExporter creates a new RedshiftExporter instance
+func NewRedshiftExporter(sess *session.Session, namespace string, logger log.Logger, enabled bool) *RedshiftExporter {
+	return &RedshiftExporter{
+		sess:    sess,
+		enabled: enabled,
+		ClusterEncrypted: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "redshift_cluster_encrypted"),
+			"Indicates if the data in the cluster is encrypted at rest.",
+			[]string{"aws_region", "cluster_identifier"},
+			nil,
+		),
+		ClusterInfo: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "redshift_cluster_info"),
+			"The node type and version of the cluster. The value is always 1.",
+			[]string{"aws_region", "cluster_identifier", "node_type", "version"},
+			nil,
+		),
+		ClusterNodeCount: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "redshift_cluster_node_count"),
+			"The number of compute nodes in the cluster.",
+			[]string{"aws_region", "cluster_identifier"},
+			nil,
+		),
+		ClusterStatus: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "redshift_cluster_status"),
+			"The status of the cluster.",
+			[]string{"aws_region", "cluster_identifier", "status"},
+			nil,
+		),
+		ResourcesTotal: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "redshift_resources_total"),
+			"The number of Redshift clusters listed by the collector.",
+			[]string{"aws_region"},
+			nil,
+		),
+		logger: logger,
+	}
+}
+
+// Name returns the name of the collector
+func (e *RedshiftExporter) Name() string {
+	return "redshift"
+}
+
+// Enabled returns true if the collector has to be registered
+func (e *RedshiftExporter) Enabled() bool {
+	return e.enabled
+}
+
+// FiltersTags returns true, the collector applies the tag filter
+func (e *RedshiftExporter) FiltersTags() bool {
+	return true
+}
+
+// Preflight checks the IAM permissions of the collector with a single call
+func (e *RedshiftExporter) Preflight() (string, error) {
+	_, err := redshift.New(e.sess).DescribeClusters(&redshift.DescribeClustersInput{MaxRecords: aws.Int64(20)})
+	return "redshift:DescribeClusters", err
+}
+
+// Describe is used by the Prometheus client to return a description of the metrics
+func (e *RedshiftExporter) Describe(ch chan<- *prometheus.Desc) {
+	ch <- e.ClusterEncrypted
+	ch <- e.ClusterInfo
+	ch <- e.ClusterNodeCount
+	ch <- e.ClusterStatus
+	ch <- e.ResourcesTotal
+}
+
+// Collect is used by the Prometheus client to collect and return the metrics values
+func (e *RedshiftExporter) Collect(ch chan<- prometheus.Metric) {
+	region := sessionRegion(e.sess)
+	svc := redshift.New(e.sess)
+	input := &redshift.DescribeClustersInput{}
+
+	// Get all clusters.
+	// If a Marker is found, do pagination until last page
+	var clusters []*redshift.Cluster
+	for {
+		exporterMetrics.IncrementRequests(redshift.ServiceName, "DescribeClusters")
+		result, err := svc.DescribeClusters(input)
+		if err != nil {
+			level.Error(e.logger).Log("msg", "Call to DescribeClusters failed", "region", region, "err", err)
+			exporterMetrics.IncrementErrors(redshift.ServiceName, "DescribeClusters", err)
+			return
+		}
+		for _, cluster := range result.Clusters {
+			tags := map[string]*string{}
+			for _, tag := range cluster.Tags {
+				tags[aws.StringValue(tag.Key)] = tag.Value
+			}
+			if !tagFilter.Includes(tags) {
+				continue
+			}
+			clusters = append(clusters, cluster)
+		}
+		input.Marker = result.Marker
+		if result.Marker == nil {
+			break
+		}
+	}
+	ch <- prometheus.MustNewConstMetric(e.ResourcesTotal, prometheus.GaugeValue, float64(len(clusters)), region)
+
+	for _, cluster := range clusters {
+		// Most attributes are not set yet while the cluster is being created
+		if cluster.ClusterIdentifier == nil {
+			continue
+		}
+		identifier := *cluster.ClusterIdentifier
+
+		ch <- prometheus.MustNewConstMetric(e.ClusterStatus, prometheus.GaugeValue, 1, region, identifier, aws.StringValue(cluster.ClusterStatus))
+		ch <- prometheus.MustNewConstMetric(e.ClusterInfo, prometheus.GaugeValue, 1, region, identifier, aws.StringValue(cluster.NodeType), aws.StringValue(cluster.ClusterVersion))
+		if cluster.NumberOfNodes != nil {
+			ch <- prometheus.MustNewConstMetric(e.ClusterNodeCount, prometheus.GaugeValue, float64(*cluster.NumberOfNodes), region, identifier)
+		}
+		if aws.BoolValue(cluster.Encrypted) {
+			ch <- prometheus.MustNewConstMetric(e.ClusterEncrypted, prometheus.GaugeValue, 1, region, identifier)
+		} else {
+			ch <- prometheus.MustNewConstMetric(e.ClusterEncrypted, prometheus.GaugeValue, 0, region, identifier)
+		}
+	}
+}
@@ -0,0 +1,260 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/cloudwatch"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// The S3 storage metrics are published to CloudWatch once a day
+const (
+	s3MetricsPeriod     = 24 * time.Hour
+	s3RefreshInterval   = time.Hour
+	s3BucketSizeMetric  = "BucketSizeBytes"
+	s3ObjectCountMetric = "NumberOfObjects"
+)
+
+// S3Exporter defines an instance of the S3 Exporter
+type S3Exporter struct {
+	sess              *session.Session
+	BucketObjectCount *prometheus.Desc
+	BucketSizeBytes   *prometheus.Desc
+	ResourcesTotal    *prometheus.Desc
+
+	enabled bool
+	logger  log.Logger
+}
+
+func init() {
+	RegisterCollector(func(sess *session.Session, namespace string, logger log.Logger) Collector {
+		return NewS3Exporter(sess, namespace, logger, *s3Enabled)
+	})
+}
+
+// NewS3Exporter creates a new S3Exporter instance
+func NewS3Exporter(sess *session.Session, namespace string, logger log.Logger, enabled bool) *S3Exporter {
+	return &S3Exporter{
+		sess:    sess,
+		enabled: enabled,
+		BucketObjectCount: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "s3_bucket_object_count"),
+			"The number of objects stored in the bucket, as reported daily to CloudWatch.",
+			[]string{"aws_region", "bucket_name"},
+			nil,
+		),
+		BucketSizeBytes: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "s3_bucket_size_bytes"),
+			"The amount of data stored in the bucket for the storage type, as reported daily to CloudWatch.",
+			[]string{"aws_region", "bucket_name", "storage_type"},
+			nil,
+		),
+		ResourcesTotal: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "s3_resources_total"),
+			"The number of S3 buckets listed by the collector.",
+			[]string{"aws_region"},
+			nil,
+		),
+		logger: logger,
+	}
+}
+
+// Name returns the name of the collector
+func (e *S3Exporter) Name() string {
+	return "s3"
+}
+
+// Enabled returns true if the collector has to be registered
+func (e *S3Exporter) Enabled() bool {
+	return e.enabled
+}
+
+// FiltersTags returns true, the collector applies the tag filter
+func (e *S3Exporter) FiltersTags() bool {
+	return true
+}
+
+// Preflight checks the IAM permissions of the collector with a single call
+func (e *S3Exporter) Preflight() (string, error) {
+	_, err := s3.New(e.sess).ListBuckets(&s3.ListBucketsInput{})
+	return "s3:ListAllMyBuckets", err
+}
+
+// RefreshInterval returns the minimum duration between two fetches of the bucket metrics
+func (e *S3Exporter) RefreshInterval() time.Duration {
+	return s3RefreshInterval
+}
+
+// Describe is used by the Prometheus client to return a description of the metrics
+func (e *S3Exporter) Describe(ch chan<- *prometheus.Desc) {
+	ch <- e.BucketObjectCount
+	ch <- e.BucketSizeBytes
+	ch <- e.ResourcesTotal
+}
+
+// Collect is used by the Prometheus client to collect and return the metrics values
+func (e *S3Exporter) Collect(ch chan<- prometheus.Metric) {
+	region := sessionRegion(e.sess)
+	buckets, err := e.listBuckets()
+	if err != nil {
+		level.Error(e.logger).Log("msg", "Could not list the buckets", "region", region, "err", err)
+		return
+	}
+	ch <- prometheus.MustNewConstMetric(e.ResourcesTotal, prometheus.GaugeValue, float64(len(buckets)), region)
+
+	// ListBuckets returns the buckets of every region, while the CloudWatch metrics
+	// only exist for the buckets of the session's region
+	cwSvc := cloudwatch.New(e.sess)
+	var metrics []*cloudwatch.Metric
+	for _, metricName := range []string{s3BucketSizeMetric, s3ObjectCountMetric} {
+		listed, err := e.listMetrics(region, cwSvc, metricName)
+		if err != nil {
+			return
+		}
+		for _, metric := range listed {
+			// Metrics of deleted buckets are still listed for two weeks
+			if buckets[s3MetricDimension(metric, "BucketName")] {
+				metrics = append(metrics, metric)
+			}
+		}
+	}
+
+	// GetMetricData accepts at most metricDataQueriesLimit queries per call
+	now := time.Now()
+	for start := 0; start < len(metrics); start += metricDataQueriesLimit {
+		end := start + metricDataQueriesLimit
+		if end > len(metrics) {
+			end = len(metrics)
+		}
+		batch := metrics[start:end]
+
+		queries := make([]*cloudwatch.MetricDataQuery, 0, len(batch))
+		for i, metric := range batch {
+			queries = append(queries, &cloudwatch.MetricDataQuery{
+				Id: aws.String(fmt.Sprintf("m%d", i)),
+				MetricStat: &cloudwatch.MetricStat{
+					Metric: metric,
+					Period: aws.Int64(int64(s3MetricsPeriod.Seconds())),
+					Stat:   aws.String(cloudwatch.StatisticAverage),
+				},
+			})
+		}
+		input := &cloudwatch.GetMetricDataInput{
+			MetricDataQueries: queries,
+			// Look back over two days as the daily datapoint is published late
+			StartTime: aws.Time(now.Add(-2 * s3MetricsPeriod)),
+			EndTime:   aws.Time(now),
+			ScanBy:    aws.String(cloudwatch.ScanByTimestampDescending),
+		}
+
+		// Get the latest datapoint of every query.
+		// If a NextToken is found, do pagination until last page
+		latest := map[string]float64{}
+		for {
+			exporterMetrics.IncrementRequests(cloudwatch.ServiceName, "GetMetricData")
+			result, err := cwSvc.GetMetricData(input)
+			if err != nil {
+				level.Error(e.logger).Log("msg", "Call to GetMetricData failed", "region", region, "err", err)
+				exporterMetrics.IncrementErrors(cloudwatch.ServiceName, "GetMetricData", err)
+				return
+			}
+			for _, data := range result.MetricDataResults {
+				if _, ok := latest[*data.Id]; !ok && len(data.Values) > 0 {
+					latest[*data.Id] = *data.Values[0]
+				}
+			}
+			input.NextToken = result.NextToken
+			if result.NextToken == nil {
+				break
+			}
+		}
+
+		for i, metric := range batch {
+			value, ok := latest[fmt.Sprintf("m%d", i)]
+			if !ok {
+				continue
+			}
+			bucketName := s3MetricDimension(metric, "BucketName")
+			if aws.StringValue(metric.MetricName) == s3BucketSizeMetric {
+				ch <- prometheus.MustNewConstMetric(e.BucketSizeBytes, prometheus.GaugeValue, value, region, bucketName, s3MetricDimension(metric, "StorageType"))
+			} else {
+				ch <- prometheus.MustNewConstMetric(e.BucketObjectCount, prometheus.GaugeValue, value, region, bucketName)
+			}
+		}
+	}
+}
+
+// listBuckets returns the set of the names of the buckets carrying the filtered tag
+func (e *S3Exporter) listBuckets() (map[string]bool, error) {
+	svc := s3.New(e.sess)
+	exporterMetrics.IncrementRequests(s3.ServiceName, "ListBuckets")
+	result, err := svc.ListBuckets(&s3.ListBucketsInput{})
+	if err != nil {
+		exporterMetrics.IncrementErrors(s3.ServiceName, "ListBuckets", err)
+		return nil, err
+	}
+
+	// The Resource Groups Tagging API filters the buckets on their tags,
+	// bucket ARNs don't include the region nor the account and end with the bucket name
+	var taggedBuckets map[string]bool
+	if tagFilter != nil {
+		names, err := tagFilter.ResourceIDs(e.sess, "s3")
+		if err != nil {
+			return nil, err
+		}
+		taggedBuckets = names
+	}
+
+	buckets := map[string]bool{}
+	for _, bucket := range result.Buckets {
+		name := aws.StringValue(bucket.Name)
+		if taggedBuckets != nil && !taggedBuckets[name] {
+			continue
+		}
+		buckets[name] = true
+	}
+	return buckets, nil
+}
+
+// listMetrics returns the S3 CloudWatch metrics of the given name of all the buckets of the region
+func (e *S3Exporter) listMetrics(region string, svc *cloudwatch.CloudWatch, metricName string) ([]*cloudwatch.Metric, error) {
+	input := &cloudwatch.ListMetricsInput{
+		Namespace:  aws.String("AWS/S3"),
+		MetricName: aws.String(metricName),
+	}
+
+	// Get all metrics.
+	// If a NextToken is found, do pagination until last page
+	var metrics []*cloudwatch.Metric
+	for {
+		exporterMetrics.IncrementRequests(cloudwatch.ServiceName, "ListMetrics")
+		result, err := svc.ListMetrics(input)
+		if err != nil {
+			level.Error(e.logger).Log("msg", "Call to ListMetrics failed", "region", region, "err", err)
+			exporterMetrics.IncrementErrors(cloudwatch.ServiceName, "ListMetrics", err)
+			return nil, err
+		}
+		metrics = append(metrics, result.Metrics...)
+		input.NextToken = result.NextToken
+		if result.NextToken == nil {
+			break
+		}
+	}
+	return metrics, nil
+}
+
+// s3MetricDimension returns the value of the dimension of the metric, or an empty string when it is not set
+func s3MetricDimension(metric *cloudwatch.Metric, name string) string {
+	for _, dimension := range metric.Dimensions {
+		if aws.StringValue(dimension.Name) == name {
+			return aws.StringValue(dimension.Value)
+		}
+	}
+	return ""
+}
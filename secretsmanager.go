@@ -0,0 +1,142 @@
+package main
+
+import (
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/secretsmanager"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// SecretsManagerExporter defines an instance of the Secrets Manager Exporter
+type SecretsManagerExporter struct {
+	sess                  *session.Session
+	DaysSinceLastChanged  *prometheus.Desc
+	DaysSinceLastRotation *prometheus.Desc
+	ResourcesTotal        *prometheus.Desc
+	RotationEnabled       *prometheus.Desc
+
+	enabled bool
+	logger  log.Logger
+}
+
+func init() {
+	RegisterCollector(func(sess *session.Session, namespace string, logger log.Logger) Collector {
+		return NewSecretsManagerExporter(sess, namespace, logger, *secretsManagerEnabled)
+	})
+}
+
+// NewSecretsManagerExporter creates a new SecretsManagerExporter instance
+func NewSecretsManagerExporter(sess *session.Session, namespace string, logger log.Logger, enabled bool) *SecretsManagerExporter {
+	return &SecretsManagerExporter{
+		sess:    sess,
+		enabled: enabled,
+		DaysSinceLastChanged: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "secretsmanager_days_since_last_changed"),
+			"The number of days since the secret was last changed.",
+			[]string{"aws_region", "secret_name"},
+			nil,
+		),
+		DaysSinceLastRotation: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "secretsmanager_days_since_last_rotation"),
+			"The number of days since the secret was last rotated.",
+			[]string{"aws_region", "secret_name"},
+			nil,
+		),
+		ResourcesTotal: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "secretsmanager_resources_total"),
+			"The number of Secrets Manager secrets listed by the collector.",
+			[]string{"aws_region"},
+			nil,
+		),
+		RotationEnabled: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "secretsmanager_rotation_enabled"),
+			"Indicates if automatic rotation is enabled for the secret.",
+			[]string{"aws_region", "secret_name"},
+			nil,
+		),
+		logger: logger,
+	}
+}
+
+// Name returns the name of the collector
+func (e *SecretsManagerExporter) Name() string {
+	return "secretsmanager"
+}
+
+// Enabled returns true if the collector has to be registered
+func (e *SecretsManagerExporter) Enabled() bool {
+	return e.enabled
+}
+
+// FiltersTags returns true, the collector applies the tag filter
+func (e *SecretsManagerExporter) FiltersTags() bool {
+	return true
+}
+
+// Preflight checks the IAM permissions of the collector with a single call
+func (e *SecretsManagerExporter) Preflight() (string, error) {
+	_, err := secretsmanager.New(e.sess).ListSecrets(&secretsmanager.ListSecretsInput{MaxResults: aws.Int64(1)})
+	return "secretsmanager:ListSecrets", err
+}
+
+// Describe is used by the Prometheus client to return a description of the metrics
+func (e *SecretsManagerExporter) Describe(ch chan<- *prometheus.Desc) {
+	ch <- e.DaysSinceLastChanged
+	ch <- e.DaysSinceLastRotation
+	ch <- e.ResourcesTotal
+	ch <- e.RotationEnabled
+}
+
+// Collect is used by the Prometheus client to collect and return the metrics values
+func (e *SecretsManagerExporter) Collect(ch chan<- prometheus.Metric) {
+	region := sessionRegion(e.sess)
+	svc := secretsmanager.New(e.sess)
+	input := &secretsmanager.ListSecretsInput{}
+
+	// Get all secrets.
+	// If a NextToken is found, do pagination until last page
+	var secrets []*secretsmanager.SecretListEntry
+	for {
+		exporterMetrics.IncrementRequests(secretsmanager.ServiceName, "ListSecrets")
+		result, err := svc.ListSecrets(input)
+		if err != nil {
+			level.Error(e.logger).Log("msg", "Call to ListSecrets failed", "region", region, "err", err)
+			exporterMetrics.IncrementErrors(secretsmanager.ServiceName, "ListSecrets", err)
+			return
+		}
+		for _, secret := range result.SecretList {
+			tags := map[string]*string{}
+			for _, tag := range secret.Tags {
+				tags[aws.StringValue(tag.Key)] = tag.Value
+			}
+			if !tagFilter.Includes(tags) {
+				continue
+			}
+			secrets = append(secrets, secret)
+		}
+		input.NextToken = result.NextToken
+		if result.NextToken == nil {
+			break
+		}
+	}
+	ch <- prometheus.MustNewConstMetric(e.ResourcesTotal, prometheus.GaugeValue, float64(len(secrets)), region)
+
+	for _, secret := range secrets {
+		if aws.BoolValue(secret.RotationEnabled) {
+			ch <- prometheus.MustNewConstMetric(e.RotationEnabled, prometheus.GaugeValue, 1, region, *secret.Name)
+		} else {
+			ch <- prometheus.MustNewConstMetric(e.RotationEnabled, prometheus.GaugeValue, 0, region, *secret.Name)
+		}
+		// LastRotatedDate is not set for secrets that were never rotated
+		if secret.LastRotatedDate != nil {
+			ch <- prometheus.MustNewConstMetric(e.DaysSinceLastRotation, prometheus.GaugeValue, time.Since(*secret.LastRotatedDate).Hours()/24, region, *secret.Name)
+		}
+		if secret.LastChangedDate != nil {
+			ch <- prometheus.MustNewConstMetric(e.DaysSinceLastChanged, prometheus.GaugeValue, time.Since(*secret.LastChangedDate).Hours()/24, region, *secret.Name)
+		}
+	}
+}
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// The CIDRs matching any IPv4 and any IPv6 address
+const (
+	anyIPv4CIDR = "0.0.0.0/0"
+	anyIPv6CIDR = "::/0"
+)
+
+// SecurityGroupExporter defines an instance of the security groups Exporter
+type SecurityGroupExporter struct {
+	sess             *session.Session
+	EgressRuleCount  *prometheus.Desc
+	IngressRuleCount *prometheus.Desc
+	OpenToWorld      *prometheus.Desc
+	ResourcesTotal   *prometheus.Desc
+
+	enabled bool
+	logger  log.Logger
+}
+
+func init() {
+	RegisterCollector(func(sess *session.Session, namespace string, logger log.Logger) Collector {
+		return NewSecurityGroupExporter(sess, namespace, logger, *securityGroupsEnabled)
+	})
+}
+
+// NewSecurityGroupExporter creates a new SecurityGroupExporter instance
+func NewSecurityGroupExporter(sess *session.Session, namespace string, logger log.Logger, enabled bool) *SecurityGroupExporter {
+	return &SecurityGroupExporter{
+		sess:    sess,
+		enabled: enabled,
+		EgressRuleCount: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "security_group_egress_rule_count"),
+			"The number of egress rules of the security group, one per CIDR, prefix list or security group of its permissions.",
+			[]string{"aws_region", "group_id", "group_name", "vpc_id"},
+			nil,
+		),
+		IngressRuleCount: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "security_group_ingress_rule_count"),
+			"The number of ingress rules of the security group, one per CIDR, prefix list or security group of its permissions.",
+			[]string{"aws_region", "group_id", "group_name", "vpc_id"},
+			nil,
+		),
+		OpenToWorld: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "security_group_open_to_world"),
+			"Indicates if any ingress rule of the security group allows "+anyIPv4CIDR+" or "+anyIPv6CIDR+".",
+			[]string{"aws_region", "group_id", "group_name", "vpc_id"},
+			nil,
+		),
+		ResourcesTotal: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "security_group_resources_total"),
+			"The number of security groups listed by the collector.",
+			[]string{"aws_region"},
+			nil,
+		),
+		logger: logger,
+	}
+}
+
+// Name returns the name of the collector
+func (e *SecurityGroupExporter) Name() string {
+	return "securitygroups"
+}
+
+// Enabled returns true if the collector has to be registered
+func (e *SecurityGroupExporter) Enabled() bool {
+	return e.enabled
+}
+
+// FiltersTags returns true, the collector applies the tag filter
+func (e *SecurityGroupExporter) FiltersTags() bool {
+	return true
+}
+
+// Preflight checks the IAM permissions of the collector with a single call
+func (e *SecurityGroupExporter) Preflight() (string, error) {
+	_, err := ec2.New(e.sess).DescribeSecurityGroups(&ec2.DescribeSecurityGroupsInput{MaxResults: aws.Int64(5)})
+	return "ec2:DescribeSecurityGroups", err
+}
+
+// Describe is used by the Prometheus client to return a description of the metrics
+func (e *SecurityGroupExporter) Describe(ch chan<- *prometheus.Desc) {
+	ch <- e.EgressRuleCount
+	ch <- e.IngressRuleCount
+	ch <- e.OpenToWorld
+	ch <- e.ResourcesTotal
+}
+
+// Collect is used by the Prometheus client to collect and return the metrics values
+func (e *SecurityGroupExporter) Collect(ch chan<- prometheus.Metric) {
+	region := sessionRegion(e.sess)
+	svc := ec2.New(e.sess)
+	input := &ec2.DescribeSecurityGroupsInput{
+		Filters: tagFilter.EC2Filters(),
+	}
+
+	// Get all security groups.
+	// If a NextToken is found, do pagination until last page
+	var groups []*ec2.SecurityGroup
+	for {
+		exporterMetrics.IncrementRequests(ec2.ServiceName, "DescribeSecurityGroups")
+		result, err := svc.DescribeSecurityGroups(input)
+		if err != nil {
+			level.Error(e.logger).Log("msg", "Call to DescribeSecurityGroups failed", "region", region, "err", err)
+			exporterMetrics.IncrementErrors(ec2.ServiceName, "DescribeSecurityGroups", err)
+			return
+		}
+		groups = append(groups, result.SecurityGroups...)
+		input.NextToken = result.NextToken
+		if result.NextToken == nil {
+			break
+		}
+	}
+	ch <- prometheus.MustNewConstMetric(e.ResourcesTotal, prometheus.GaugeValue, float64(len(groups)), region)
+
+	for _, group := range groups {
+		groupName := aws.StringValue(group.GroupName)
+		vpcID := aws.StringValue(group.VpcId)
+		ch <- prometheus.MustNewConstMetric(e.IngressRuleCount, prometheus.GaugeValue, float64(countRules(group.IpPermissions)), region, *group.GroupId, groupName, vpcID)
+		ch <- prometheus.MustNewConstMetric(e.EgressRuleCount, prometheus.GaugeValue, float64(countRules(group.IpPermissionsEgress)), region, *group.GroupId, groupName, vpcID)
+
+		// Egress is open to the world in the default rule of every group, only ingress is checked
+		if isOpenToWorld(group.IpPermissions) {
+			ch <- prometheus.MustNewConstMetric(e.OpenToWorld, prometheus.GaugeValue, 1, region, *group.GroupId, groupName, vpcID)
+		} else {
+			ch <- prometheus.MustNewConstMetric(e.OpenToWorld, prometheus.GaugeValue, 0, region, *group.GroupId, groupName, vpcID)
+		}
+	}
+}
+
+// countRules returns the number of rules of the permissions, a permission holds one rule per source or destination
+func countRules(permissions []*ec2.IpPermission) int {
+	count := 0
+	for _, permission := range permissions {
+		count += len(permission.IpRanges) + len(permission.Ipv6Ranges) + len(permission.PrefixListIds) + len(permission.UserIdGroupPairs)
+	}
+	return count
+}
+
+// isOpenToWorld returns true if any of the rules allows any IPv4 or IPv6 address
+func isOpenToWorld(permissions []*ec2.IpPermission) bool {
+	for _, permission := range permissions {
+		for _, ipRange := range permission.IpRanges {
+			if aws.StringValue(ipRange.CidrIp) == anyIPv4CIDR {
+				return true
+			}
+		}
+		for _, ipRange := range permission.Ipv6Ranges {
+			if aws.StringValue(ipRange.CidrIpv6) == anyIPv6CIDR {
+				return true
+			}
+		}
+	}
+	return false
+}
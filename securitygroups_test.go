@@ -0,0 +1,58 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+)
+
+func TestSecurityGroupRules(t *testing.T) {
+	tests := []struct {
+		name        string
+		permissions []*ec2.IpPermission
+		wantRules   int
+		wantOpen    bool
+	}{
+		{
+			name: "no permissions",
+		},
+		{
+			name: "several sources in a permission",
+			permissions: []*ec2.IpPermission{{
+				IpRanges:         []*ec2.IpRange{{CidrIp: aws.String("10.0.0.0/8")}, {CidrIp: aws.String("192.168.0.0/16")}},
+				Ipv6Ranges:       []*ec2.Ipv6Range{{CidrIpv6: aws.String("fd00::/8")}},
+				PrefixListIds:    []*ec2.PrefixListId{{PrefixListId: aws.String("pl-1")}},
+				UserIdGroupPairs: []*ec2.UserIdGroupPair{{GroupId: aws.String("sg-1")}},
+			}},
+			wantRules: 5,
+		},
+		{
+			name: "open to any IPv4 address",
+			permissions: []*ec2.IpPermission{
+				{UserIdGroupPairs: []*ec2.UserIdGroupPair{{GroupId: aws.String("sg-1")}}},
+				{IpRanges: []*ec2.IpRange{{CidrIp: aws.String(anyIPv4CIDR)}}},
+			},
+			wantRules: 2,
+			wantOpen:  true,
+		},
+		{
+			name: "open to any IPv6 address",
+			permissions: []*ec2.IpPermission{
+				{Ipv6Ranges: []*ec2.Ipv6Range{{CidrIpv6: aws.String(anyIPv6CIDR)}}},
+			},
+			wantRules: 1,
+			wantOpen:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := countRules(tt.permissions); got != tt.wantRules {
+				t.Errorf("countRules() = %d, want %d", got, tt.wantRules)
+			}
+			if got := isOpenToWorld(tt.permissions); got != tt.wantOpen {
+				t.Errorf("isOpenToWorld() = %v, want %v", got, tt.wantOpen)
+			}
+		})
+	}
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/sns"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// SNSExporter defines an instance of the SNS Exporter
+type SNSExporter struct {
+	sess                 *session.Session
+	ResourcesTotal       *prometheus.Desc
+	SubscriptionCount    *prometheus.Desc
+	SubscriptionsPending *prometheus.Desc
+
+	enabled bool
+	logger  log.Logger
+}
+
+func init() {
+	RegisterCollector(func(sess *session.Session, namespace string, logger log.Logger) Collector {
+		return NewSNSExporter(sess, namespace, logger, *snsEnabled)
+	})
+}
+
+// NewSNSExporter creates a new SNSExporter instance
+func NewSNSExporter(sess *session.Session, namespace string, logger log.Logger, enabled bool) *SNSExporter {
+	return &SNSExporter{
+		sess:    sess,
+		enabled: enabled,
+		ResourcesTotal: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "sns_resources_total"),
+			"The number of SNS topics listed by the collector.",
+			[]string{"aws_region"},
+			nil,
+		),
+		SubscriptionCount: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "sns_topic_subscription_count"),
+			"The number of confirmed subscriptions of the topic.",
+			[]string{"aws_region", "topic_name"},
+			nil,
+		),
+		SubscriptionsPending: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "sns_topic_subscriptions_pending"),
+			"The number of subscriptions of the topic pending confirmation.",
+			[]string{"aws_region", "topic_name"},
+			nil,
+		),
+		logger: logger,
+	}
+}
+
+// Name returns the name of the collector
+func (e *SNSExporter) Name() string {
+	return "sns"
+}
+
+// Enabled returns true if the collector has to be registered
+func (e *SNSExporter) Enabled() bool {
+	return e.enabled
+}
+
+// FiltersTags returns true, the collector applies the tag filter
+func (e *SNSExporter) FiltersTags() bool {
+	return true
+}
+
+// Preflight checks the IAM permissions of the collector with a single call
+func (e *SNSExporter) Preflight() (string, error) {
+	_, err := sns.New(e.sess).ListTopics(&sns.ListTopicsInput{})
+	return "sns:ListTopics", err
+}
+
+// Describe is used by the Prometheus client to return a description of the metrics
+func (e *SNSExporter) Describe(ch chan<- *prometheus.Desc) {
+	ch <- e.ResourcesTotal
+	ch <- e.SubscriptionCount
+	ch <- e.SubscriptionsPending
+}
+
+// Collect is used by the Prometheus client to collect and return the metrics values
+func (e *SNSExporter) Collect(ch chan<- prometheus.Metric) {
+	region := sessionRegion(e.sess)
+	svc := sns.New(e.sess)
+
+	// The Resource Groups Tagging API filters the topics on their tags
+	var taggedTopics map[string]bool
+	if tagFilter != nil {
+		arns, err := tagFilter.ResourceARNs(e.sess, "sns")
+		if err != nil {
+			level.Error(e.logger).Log("msg", "Call to GetResources failed", "region", region, "err", err)
+			return
+		}
+		taggedTopics = arns
+	}
+
+	input := &sns.ListTopicsInput{}
+
+	// Get all topics.
+	// If a NextToken is found, do pagination until last page
+	var topics []*sns.Topic
+	for {
+		exporterMetrics.IncrementRequests(sns.ServiceName, "ListTopics")
+		result, err := svc.ListTopics(input)
+		if err != nil {
+			level.Error(e.logger).Log("msg", "Call to ListTopics failed", "region", region, "err", err)
+			exporterMetrics.IncrementErrors(sns.ServiceName, "ListTopics", err)
+			return
+		}
+		for _, topic := range result.Topics {
+			if taggedTopics != nil && !taggedTopics[*topic.TopicArn] {
+				continue
+			}
+			topics = append(topics, topic)
+		}
+		input.NextToken = result.NextToken
+		if result.NextToken == nil {
+			break
+		}
+	}
+	ch <- prometheus.MustNewConstMetric(e.ResourcesTotal, prometheus.GaugeValue, float64(len(topics)), region)
+
+	for _, topic := range topics {
+		exporterMetrics.IncrementRequests(sns.ServiceName, "GetTopicAttributes")
+		result, err := svc.GetTopicAttributes(&sns.GetTopicAttributesInput{TopicArn: topic.TopicArn})
+		if err != nil {
+			level.Error(e.logger).Log("msg", "Call to GetTopicAttributes failed", "region", region, "topic", *topic.TopicArn, "err", err)
+			exporterMetrics.IncrementErrors(sns.ServiceName, "GetTopicAttributes", err)
+			continue
+		}
+
+		topicName := snsTopicName(*topic.TopicArn)
+		if value, ok := parseNumericAttribute(result.Attributes, "SubscriptionsConfirmed"); ok {
+			ch <- prometheus.MustNewConstMetric(e.SubscriptionCount, prometheus.GaugeValue, value, region, topicName)
+		}
+		if value, ok := parseNumericAttribute(result.Attributes, "SubscriptionsPending"); ok {
+			ch <- prometheus.MustNewConstMetric(e.SubscriptionsPending, prometheus.GaugeValue, value, region, topicName)
+		}
+	}
+}
+
+// snsTopicName returns the name of the topic, which is the last element of its ARN
+func snsTopicName(topicArn string) string {
+	return topicArn[strings.LastIndex(topicArn, ":")+1:]
+}
@@ -0,0 +1,264 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/cloudwatch"
+	"github.com/aws/aws-sdk-go/service/sqs"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// sqsListQueuesPageSize is the maximum number of queue URLs ListQueues returns per page
+const sqsListQueuesPageSize = 1000
+
+// SQS publishes its CloudWatch metrics every 5 minutes
+const sqsMetricsPeriod = 5 * time.Minute
+
+// SQSExporter defines an instance of the SQS Exporter
+type SQSExporter struct {
+	sess               *session.Session
+	AgeOfOldestMessage *prometheus.Desc
+	MessagesCount      *prometheus.Desc
+	MessagesNotVisible *prometheus.Desc
+	ResourcesTotal     *prometheus.Desc
+
+	enabled bool
+	logger  log.Logger
+}
+
+func init() {
+	RegisterCollector(func(sess *session.Session, namespace string, logger log.Logger) Collector {
+		return NewSQSExporter(sess, namespace, logger, *sqsEnabled)
+	})
+}
+
+// NewSQSExporter creates a new SQSExporter instance
+func NewSQSExporter(sess *session.Session, namespace string, logger log.Logger, enabled bool) *SQSExporter {
+	return &SQSExporter{
+		sess:    sess,
+		enabled: enabled,
+		AgeOfOldestMessage: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "sqs_approximate_age_of_oldest_message_seconds"),
+			"The approximate age of the oldest message in the queue, from the ApproximateAgeOfOldestMessage CloudWatch metric.",
+			[]string{"aws_region", "queue_name"},
+			nil,
+		),
+		MessagesCount: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "sqs_approximate_number_of_messages"),
+			"The approximate number of messages available for retrieval from the queue.",
+			[]string{"aws_region", "queue_name"},
+			nil,
+		),
+		MessagesNotVisible: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "sqs_approximate_number_of_messages_not_visible"),
+			"The approximate number of messages in flight, received but not yet deleted from the queue.",
+			[]string{"aws_region", "queue_name"},
+			nil,
+		),
+		ResourcesTotal: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "sqs_resources_total"),
+			"The number of SQS queues listed by the collector.",
+			[]string{"aws_region"},
+			nil,
+		),
+		logger: logger,
+	}
+}
+
+// Name returns the name of the collector
+func (e *SQSExporter) Name() string {
+	return "sqs"
+}
+
+// Enabled returns true if the collector has to be registered
+func (e *SQSExporter) Enabled() bool {
+	return e.enabled
+}
+
+// FiltersTags returns true, the collector applies the tag filter
+func (e *SQSExporter) FiltersTags() bool {
+	return true
+}
+
+// Preflight checks the IAM permissions of the collector with a single call
+func (e *SQSExporter) Preflight() (string, error) {
+	_, err := sqs.New(e.sess).ListQueues(&sqs.ListQueuesInput{})
+	return "sqs:ListQueues", err
+}
+
+// Describe is used by the Prometheus client to return a description of the metrics
+func (e *SQSExporter) Describe(ch chan<- *prometheus.Desc) {
+	ch <- e.AgeOfOldestMessage
+	ch <- e.MessagesCount
+	ch <- e.MessagesNotVisible
+	ch <- e.ResourcesTotal
+}
+
+// Collect is used by the Prometheus client to collect and return the metrics values
+func (e *SQSExporter) Collect(ch chan<- prometheus.Metric) {
+	region := sessionRegion(e.sess)
+	svc := sqs.New(e.sess)
+
+	// The Resource Groups Tagging API filters the queues on their tags, the queues are listed by URL
+	var taggedQueues map[string]bool
+	if tagFilter != nil {
+		names, err := tagFilter.ResourceIDs(e.sess, "sqs")
+		if err != nil {
+			level.Error(e.logger).Log("msg", "Call to GetResources failed", "region", region, "err", err)
+			return
+		}
+		taggedQueues = names
+	}
+
+	// Get all queues.
+	// If a NextToken is found, do pagination until last page
+	var allQueueURLs []*string
+	input := &sqs.ListQueuesInput{MaxResults: aws.Int64(sqsListQueuesPageSize)}
+	for {
+		exporterMetrics.IncrementRequests(sqs.ServiceName, "ListQueues")
+		result, err := svc.ListQueues(input)
+		if err != nil {
+			level.Error(e.logger).Log("msg", "Call to ListQueues failed", "region", region, "err", err)
+			exporterMetrics.IncrementErrors(sqs.ServiceName, "ListQueues", err)
+			return
+		}
+		allQueueURLs = append(allQueueURLs, result.QueueUrls...)
+		input.NextToken = result.NextToken
+		if result.NextToken == nil {
+			break
+		}
+	}
+	var queueURLs []*string
+	for _, queueURL := range allQueueURLs {
+		if taggedQueues != nil && !taggedQueues[sqsQueueName(*queueURL)] {
+			continue
+		}
+		queueURLs = append(queueURLs, queueURL)
+	}
+	ch <- prometheus.MustNewConstMetric(e.ResourcesTotal, prometheus.GaugeValue, float64(len(queueURLs)), region)
+
+	queueNames := make([]string, 0, len(queueURLs))
+	for _, queueURL := range queueURLs {
+		queueName := sqsQueueName(*queueURL)
+		queueNames = append(queueNames, queueName)
+
+		exporterMetrics.IncrementRequests(sqs.ServiceName, "GetQueueAttributes")
+		attributes, err := svc.GetQueueAttributes(&sqs.GetQueueAttributesInput{
+			QueueUrl: queueURL,
+			AttributeNames: aws.StringSlice([]string{
+				sqs.QueueAttributeNameApproximateNumberOfMessages,
+				sqs.QueueAttributeNameApproximateNumberOfMessagesNotVisible,
+			}),
+		})
+		if err != nil {
+			level.Error(e.logger).Log("msg", "Call to GetQueueAttributes failed", "region", region, "queue", *queueURL, "err", err)
+			exporterMetrics.IncrementErrors(sqs.ServiceName, "GetQueueAttributes", err)
+			continue
+		}
+
+		if value, ok := parseNumericAttribute(attributes.Attributes, sqs.QueueAttributeNameApproximateNumberOfMessages); ok {
+			ch <- prometheus.MustNewConstMetric(e.MessagesCount, prometheus.GaugeValue, value, region, queueName)
+		}
+		if value, ok := parseNumericAttribute(attributes.Attributes, sqs.QueueAttributeNameApproximateNumberOfMessagesNotVisible); ok {
+			ch <- prometheus.MustNewConstMetric(e.MessagesNotVisible, prometheus.GaugeValue, value, region, queueName)
+		}
+	}
+
+	// The age of the oldest message is not a queue attribute, it is only published to CloudWatch
+	e.collectAgeOfOldestMessage(ch, region, queueNames)
+}
+
+// collectAgeOfOldestMessage collects the age of the oldest message of the queues from CloudWatch
+func (e *SQSExporter) collectAgeOfOldestMessage(ch chan<- prometheus.Metric, region string, queueNames []string) {
+	svc := cloudwatch.New(e.sess)
+	now := time.Now()
+
+	// GetMetricData accepts at most metricDataQueriesLimit queries per call
+	for start := 0; start < len(queueNames); start += metricDataQueriesLimit {
+		end := start + metricDataQueriesLimit
+		if end > len(queueNames) {
+			end = len(queueNames)
+		}
+		batch := queueNames[start:end]
+
+		queries := make([]*cloudwatch.MetricDataQuery, 0, len(batch))
+		for i, queueName := range batch {
+			queries = append(queries, &cloudwatch.MetricDataQuery{
+				Id: aws.String(fmt.Sprintf("q%d", i)),
+				MetricStat: &cloudwatch.MetricStat{
+					Metric: &cloudwatch.Metric{
+						Namespace:  aws.String("AWS/SQS"),
+						MetricName: aws.String("ApproximateAgeOfOldestMessage"),
+						Dimensions: []*cloudwatch.Dimension{{
+							Name:  aws.String("QueueName"),
+							Value: aws.String(queueName),
+						}},
+					},
+					Period: aws.Int64(int64(sqsMetricsPeriod.Seconds())),
+					Stat:   aws.String(cloudwatch.StatisticMaximum),
+				},
+			})
+		}
+		input := &cloudwatch.GetMetricDataInput{
+			MetricDataQueries: queries,
+			// Look back over a few periods as the latest datapoint can be published late
+			StartTime: aws.Time(now.Add(-3 * sqsMetricsPeriod)),
+			EndTime:   aws.Time(now),
+			ScanBy:    aws.String(cloudwatch.ScanByTimestampDescending),
+		}
+
+		// Get the latest datapoint of every query.
+		// If a NextToken is found, do pagination until last page
+		latest := map[string]float64{}
+		for {
+			exporterMetrics.IncrementRequests(cloudwatch.ServiceName, "GetMetricData")
+			result, err := svc.GetMetricData(input)
+			if err != nil {
+				level.Error(e.logger).Log("msg", "Call to GetMetricData failed", "region", region, "err", err)
+				exporterMetrics.IncrementErrors(cloudwatch.ServiceName, "GetMetricData", err)
+				return
+			}
+			for _, data := range result.MetricDataResults {
+				if _, ok := latest[*data.Id]; !ok && len(data.Values) > 0 {
+					latest[*data.Id] = *data.Values[0]
+				}
+			}
+			input.NextToken = result.NextToken
+			if result.NextToken == nil {
+				break
+			}
+		}
+
+		for i, queueName := range batch {
+			if age, ok := latest[fmt.Sprintf("q%d", i)]; ok {
+				ch <- prometheus.MustNewConstMetric(e.AgeOfOldestMessage, prometheus.GaugeValue, age, region, queueName)
+			}
+		}
+	}
+}
+
+// sqsQueueName returns the name of the queue, which is the last element of its URL
+func sqsQueueName(queueURL string) string {
+	return queueURL[strings.LastIndex(queueURL, "/")+1:]
+}
+
+// parseNumericAttribute returns the numeric value of the attribute.
+// SQS and SNS return all the attribute values as strings.
+func parseNumericAttribute(attributes map[string]*string, name string) (float64, bool) {
+	value, ok := attributes[name]
+	if !ok || value == nil {
+		return 0, false
+	}
+	f, err := strconv.ParseFloat(*value, 64)
+	if err != nil {
+		return 0, false
+	}
+	return f, true
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-kit/kit/log"
+)
+
+func TestSQSListQueuesPagination(t *testing.T) {
+	// ListQueues returns two pages of queues, the other calls fail
+	pages := map[string]string{
+		"":      `{"QueueUrls":["https://sqs.us-east-1.amazonaws.com/123456789012/first"],"NextToken":"page2"}`,
+		"page2": `{"QueueUrls":["https://sqs.us-east-1.amazonaws.com/123456789012/second"]}`,
+	}
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/x-amz-json-1.0")
+		if r.Header.Get("X-Amz-Target") != "AmazonSQS.ListQueues" {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"__type":"InvalidAction","message":"Test error"}`)
+			return
+		}
+		calls++
+		var input struct {
+			MaxResults int
+			NextToken  string
+		}
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+			t.Errorf("invalid ListQueues request: %v", err)
+		}
+		if input.MaxResults != sqsListQueuesPageSize {
+			t.Errorf("ListQueues MaxResults = %d, want %d", input.MaxResults, sqsListQueuesPageSize)
+		}
+		fmt.Fprint(w, pages[input.NextToken])
+	}))
+	defer server.Close()
+
+	samples := collectSamples(t, NewSQSExporter(newTestSession(server), defaultNamespace, log.NewNopLogger(), true))
+	if calls != len(pages) {
+		t.Errorf("ListQueues calls = %d, want %d", calls, len(pages))
+	}
+	total := fmt.Sprintf("%s_sqs_resources_total{aws_region=%q}", defaultNamespace, testRegion)
+	if got := samples[total]; got != 2 {
+		t.Errorf("%s = %v, want 2", total, got)
+	}
+}
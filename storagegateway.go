@@ -0,0 +1,144 @@
+package main
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/storagegateway"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// StorageGatewayExporter defines an instance of the Storage Gateway Exporter
+type StorageGatewayExporter struct {
+	sess           *session.Session
+	CacheUsedBytes *prometheus.Desc
+	Info           *prometheus.Desc
+	ResourcesTotal *prometheus.Desc
+
+	enabled bool
+	logger  log.Logger
+}
+
+func init() {
+	RegisterCollector(func(sess *session.Session, namespace string, logger log.Logger) Collector {
+		return NewStorageGatewayExporter(sess, namespace, logger, *storageGatewayEnabled)
+	})
+}
+
+// NewStorageGatewayExporter creates a new StorageGatewayExporter instance
+func NewStorageGatewayExporter(sess *session.Session, namespace string, logger log.Logger, enabled bool) *StorageGatewayExporter {
+	return &StorageGatewayExporter{
+		sess:    sess,
+		enabled: enabled,
+		CacheUsedBytes: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "storagegateway_cache_used_bytes"),
+			"The amount of the cache of the gateway in use, in bytes.",
+			[]string{"aws_region", "gateway_id"},
+			nil,
+		),
+		Info: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "storagegateway_info"),
+			"The type and operational state of the gateway. The value is always 1.",
+			[]string{"aws_region", "gateway_id", "gateway_type", "status"},
+			nil,
+		),
+		ResourcesTotal: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "storagegateway_resources_total"),
+			"The number of Storage Gateway gateways listed by the collector.",
+			[]string{"aws_region"},
+			nil,
+		),
+		logger: logger,
+	}
+}
+
+// Name returns the name of the collector
+func (e *StorageGatewayExporter) Name() string {
+	return "storagegateway"
+}
+
+// Enabled returns true if the collector has to be registered
+func (e *StorageGatewayExporter) Enabled() bool {
+	return e.enabled
+}
+
+// FiltersTags returns true, the collector applies the tag filter
+func (e *StorageGatewayExporter) FiltersTags() bool {
+	return true
+}
+
+// Preflight checks the IAM permissions of the collector with a single call
+func (e *StorageGatewayExporter) Preflight() (string, error) {
+	_, err := storagegateway.New(e.sess).ListGateways(&storagegateway.ListGatewaysInput{Limit: aws.Int64(1)})
+	return "storagegateway:ListGateways", err
+}
+
+// Describe is used by the Prometheus client to return a description of the metrics
+func (e *StorageGatewayExporter) Describe(ch chan<- *prometheus.Desc) {
+	ch <- e.CacheUsedBytes
+	ch <- e.Info
+	ch <- e.ResourcesTotal
+}
+
+// Collect is used by the Prometheus client to collect and return the metrics values
+func (e *StorageGatewayExporter) Collect(ch chan<- prometheus.Metric) {
+	region := sessionRegion(e.sess)
+	svc := storagegateway.New(e.sess)
+
+	// The Resource Groups Tagging API filters the gateways on their tags
+	var taggedGateways map[string]bool
+	if tagFilter != nil {
+		arns, err := tagFilter.ResourceARNs(e.sess, "storagegateway:gateway")
+		if err != nil {
+			level.Error(e.logger).Log("msg", "Call to GetResources failed", "region", region, "err", err)
+			return
+		}
+		taggedGateways = arns
+	}
+
+	input := &storagegateway.ListGatewaysInput{}
+
+	// Get all gateways.
+	// If a Marker is found, do pagination until last page
+	var gateways []*storagegateway.GatewayInfo
+	for {
+		exporterMetrics.IncrementRequests(storagegateway.ServiceName, "ListGateways")
+		result, err := svc.ListGateways(input)
+		if err != nil {
+			level.Error(e.logger).Log("msg", "Call to ListGateways failed", "region", region, "err", err)
+			exporterMetrics.IncrementErrors(storagegateway.ServiceName, "ListGateways", err)
+			return
+		}
+		for _, gateway := range result.Gateways {
+			if taggedGateways != nil && !taggedGateways[aws.StringValue(gateway.GatewayARN)] {
+				continue
+			}
+			gateways = append(gateways, gateway)
+		}
+		input.Marker = result.Marker
+		if result.Marker == nil {
+			break
+		}
+	}
+	ch <- prometheus.MustNewConstMetric(e.ResourcesTotal, prometheus.GaugeValue, float64(len(gateways)), region)
+
+	for _, gateway := range gateways {
+		gatewayID := aws.StringValue(gateway.GatewayId)
+		ch <- prometheus.MustNewConstMetric(e.Info, prometheus.GaugeValue, 1, region, gatewayID, aws.StringValue(gateway.GatewayType), aws.StringValue(gateway.GatewayOperationalState))
+
+		// The cache of an offline gateway can't be described, its other metrics are still exported
+		exporterMetrics.IncrementRequests(storagegateway.ServiceName, "DescribeCache")
+		result, err := svc.DescribeCache(&storagegateway.DescribeCacheInput{
+			GatewayARN: gateway.GatewayARN,
+		})
+		if err != nil {
+			level.Warn(e.logger).Log("msg", "Call to DescribeCache failed", "region", region, "gateway", gatewayID, "err", err)
+			exporterMetrics.IncrementErrors(storagegateway.ServiceName, "DescribeCache", err)
+			continue
+		}
+		if result.CacheAllocatedInBytes != nil && result.CacheUsedPercentage != nil {
+			ch <- prometheus.MustNewConstMetric(e.CacheUsedBytes, prometheus.GaugeValue, float64(*result.CacheAllocatedInBytes)**result.CacheUsedPercentage/100, region, gatewayID)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/arn"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/resourcegroupstaggingapi"
+)
+
+// TagFilter restricts the exported resources to the ones carrying a given tag.
+// Depending on what the AWS APIs support, collectors apply it in one of three ways:
+//   - server-side with EC2 tag filters: ec2, securitygroups, transitgateway, vpc
+//   - through the ARNs or IDs returned by the Resource Groups Tagging API: batch, cloudfront, cloudwatchalarms, dms,
+//     ecr, elasticache, elb, eventbridge, glue, kinesis, kms, rds (instances), s3, sns, sqs, storagegateway, wafv2, workspaces
+//   - client-side from the tags included in the API responses: apigateway, autoscaling, ecs, efs, eks, fsx, redshift, secretsmanager
+//
+// The resources are filtered before being counted in the resources_total metrics. The collectors reporting
+// account-level data (cloudtrail, cloudwatch, cost, guardduty, health, iam, organizations, quotas, rdsevents)
+// are not filtered, a warning is logged at startup for every collector which doesn't implement TagFilteringCollector.
+type TagFilter struct {
+	Key   string
+	Value string
+}
+
+// ParseTagFilter parses a key=value tag filter, it returns nil when the filter is empty
+func ParseTagFilter(filter string) (*TagFilter, error) {
+	if filter == "" {
+		return nil, nil
+	}
+	parts := strings.SplitN(filter, "=", 2)
+	if len(parts) != 2 || parts[0] == "" {
+		return nil, fmt.Errorf("invalid tag filter %q, expected key=value", filter)
+	}
+	return &TagFilter{Key: parts[0], Value: parts[1]}, nil
+}
+
+// Includes returns true if the tags contain the filtered tag, or if there is no filter
+func (f *TagFilter) Includes(tags map[string]*string) bool {
+	if f == nil {
+		return true
+	}
+	value, ok := tags[f.Key]
+	return ok && aws.StringValue(value) == f.Value
+}
+
+// EC2Filters returns the filters to add to the EC2 Describe calls, or nil if there is no filter
+func (f *TagFilter) EC2Filters() []*ec2.Filter {
+	if f == nil {
+		return nil
+	}
+	return []*ec2.Filter{{
+		Name:   aws.String("tag:" + f.Key),
+		Values: aws.StringSlice([]string{f.Value}),
+	}}
+}
+
+// ResourceARNs returns the set of ARNs of the resources of the given types carrying the filtered tag
+func (f *TagFilter) ResourceARNs(sess *session.Session, resourceTypes ...string) (map[string]bool, error) {
+	svc := resourcegroupstaggingapi.New(sess)
+	input := &resourcegroupstaggingapi.GetResourcesInput{
+		ResourceTypeFilters: aws.StringSlice(resourceTypes),
+		TagFilters: []*resourcegroupstaggingapi.TagFilter{{
+			Key:    aws.String(f.Key),
+			Values: aws.StringSlice([]string{f.Value}),
+		}},
+	}
+
+	// Get all tagged resources.
+	// If a PaginationToken is found, do pagination until last page
+	arns := map[string]bool{}
+	for {
+		exporterMetrics.IncrementRequests(resourcegroupstaggingapi.ServiceName, "GetResources")
+		result, err := svc.GetResources(input)
+		if err != nil {
+			exporterMetrics.IncrementErrors(resourcegroupstaggingapi.ServiceName, "GetResources", err)
+			return nil, err
+		}
+		for _, resource := range result.ResourceTagMappingList {
+			arns[aws.StringValue(resource.ResourceARN)] = true
+		}
+		input.PaginationToken = result.PaginationToken
+		if aws.StringValue(result.PaginationToken) == "" {
+			break
+		}
+	}
+	return arns, nil
+}
+
+// ResourceIDs returns the set of IDs of the resources of the given types carrying the filtered tag, for the APIs
+// listing the resources without their ARN. The ID is the last element of the resource part of the ARN, such as
+// the queue name of arn:aws:sqs:us-east-1:123456789012:jobs or the stream name of arn:aws:kinesis:us-east-1:123456789012:stream/events
+func (f *TagFilter) ResourceIDs(sess *session.Session, resourceTypes ...string) (map[string]bool, error) {
+	arns, err := f.ResourceARNs(sess, resourceTypes...)
+	if err != nil {
+		return nil, err
+	}
+	ids := make(map[string]bool, len(arns))
+	for resourceARN := range arns {
+		parsed, err := arn.Parse(resourceARN)
+		if err != nil {
+			continue
+		}
+		ids[parsed.Resource[strings.LastIndexAny(parsed.Resource, "/:")+1:]] = true
+	}
+	return ids, nil
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestTagFilterResourceIDs(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/x-amz-json-1.1")
+		w.Write([]byte(`{"ResourceTagMappingList":[
+			{"ResourceARN":"arn:aws:sqs:us-east-1:123456789012:jobs"},
+			{"ResourceARN":"arn:aws:kinesis:us-east-1:123456789012:stream/events"},
+			{"ResourceARN":"arn:aws:elasticache:us-east-1:123456789012:replicationgroup:cache"},
+			{"ResourceARN":"arn:aws:s3:::bucket"}
+		]}`))
+	}))
+	defer server.Close()
+
+	filter := &TagFilter{Key: "team", Value: "platform"}
+	ids, err := filter.ResourceIDs(newTestSession(server), "sqs", "kinesis:stream", "elasticache:replicationgroup", "s3")
+	if err != nil {
+		t.Fatalf("ResourceIDs() error = %v", err)
+	}
+	want := map[string]bool{"jobs": true, "events": true, "cache": true, "bucket": true}
+	if !reflect.DeepEqual(ids, want) {
+		t.Errorf("ResourceIDs() = %v, want %v", ids, want)
+	}
+}
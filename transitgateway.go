@@ -0,0 +1,150 @@
+package main
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// TransitGatewayExporter defines an instance of the Transit Gateway Exporter
+type TransitGatewayExporter struct {
+	sess            *session.Session
+	AttachmentState *prometheus.Desc
+	Info            *prometheus.Desc
+	ResourcesTotal  *prometheus.Desc
+
+	enabled bool
+	logger  log.Logger
+}
+
+func init() {
+	RegisterCollector(func(sess *session.Session, namespace string, logger log.Logger) Collector {
+		return NewTransitGatewayExporter(sess, namespace, logger, *transitGatewayEnabled)
+	})
+}
+
+// NewTransitGatewayExporter creates a new TransitGatewayExporter instance
+func NewTransitGatewayExporter(sess *session.Session, namespace string, logger log.Logger, enabled bool) *TransitGatewayExporter {
+	return &TransitGatewayExporter{
+		sess:    sess,
+		enabled: enabled,
+		AttachmentState: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "transitgateway_attachment_state"),
+			"The state of the transit gateway attachment.",
+			[]string{"aws_region", "transit_gateway_id", "attachment_id", "resource_type", "state"},
+			nil,
+		),
+		Info: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "transitgateway_info"),
+			"The state of the transit gateway. The value is always 1.",
+			[]string{"aws_region", "transit_gateway_id", "state"},
+			nil,
+		),
+		ResourcesTotal: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "transitgateway_resources_total"),
+			"The number of transit gateways listed by the collector.",
+			[]string{"aws_region"},
+			nil,
+		),
+		logger: logger,
+	}
+}
+
+// Name returns the name of the collector
+func (e *TransitGatewayExporter) Name() string {
+	return "transitgateway"
+}
+
+// Enabled returns true if the collector has to be registered
+func (e *TransitGatewayExporter) Enabled() bool {
+	return e.enabled
+}
+
+// FiltersTags returns true, the collector applies the tag filter
+func (e *TransitGatewayExporter) FiltersTags() bool {
+	return true
+}
+
+// Preflight checks the IAM permissions of the collector with a single call
+func (e *TransitGatewayExporter) Preflight() (string, error) {
+	_, err := ec2.New(e.sess).DescribeTransitGateways(&ec2.DescribeTransitGatewaysInput{MaxResults: aws.Int64(5)})
+	return "ec2:DescribeTransitGateways", err
+}
+
+// Describe is used by the Prometheus client to return a description of the metrics
+func (e *TransitGatewayExporter) Describe(ch chan<- *prometheus.Desc) {
+	ch <- e.AttachmentState
+	ch <- e.Info
+	ch <- e.ResourcesTotal
+}
+
+// Collect is used by the Prometheus client to collect and return the metrics values
+func (e *TransitGatewayExporter) Collect(ch chan<- prometheus.Metric) {
+	region := sessionRegion(e.sess)
+	svc := ec2.New(e.sess)
+	e.collectTransitGateways(ch, region, svc)
+	e.collectAttachments(ch, region, svc)
+}
+
+// collectTransitGateways collects the state of all the transit gateways
+func (e *TransitGatewayExporter) collectTransitGateways(ch chan<- prometheus.Metric, region string, svc *ec2.EC2) {
+	input := &ec2.DescribeTransitGatewaysInput{
+		Filters: tagFilter.EC2Filters(),
+	}
+
+	// Get all transit gateways.
+	// If a NextToken is found, do pagination until last page
+	var gateways []*ec2.TransitGateway
+	for {
+		exporterMetrics.IncrementRequests(ec2.ServiceName, "DescribeTransitGateways")
+		result, err := svc.DescribeTransitGateways(input)
+		if err != nil {
+			level.Error(e.logger).Log("msg", "Call to DescribeTransitGateways failed", "region", region, "err", err)
+			exporterMetrics.IncrementErrors(ec2.ServiceName, "DescribeTransitGateways", err)
+			return
+		}
+		gateways = append(gateways, result.TransitGateways...)
+		input.NextToken = result.NextToken
+		if result.NextToken == nil {
+			break
+		}
+	}
+	ch <- prometheus.MustNewConstMetric(e.ResourcesTotal, prometheus.GaugeValue, float64(len(gateways)), region)
+
+	for _, gateway := range gateways {
+		ch <- prometheus.MustNewConstMetric(e.Info, prometheus.GaugeValue, 1, region, *gateway.TransitGatewayId, aws.StringValue(gateway.State))
+	}
+}
+
+// collectAttachments collects the state of all the transit gateway attachments
+func (e *TransitGatewayExporter) collectAttachments(ch chan<- prometheus.Metric, region string, svc *ec2.EC2) {
+	input := &ec2.DescribeTransitGatewayAttachmentsInput{
+		Filters: tagFilter.EC2Filters(),
+	}
+
+	// Get all transit gateway attachments.
+	// If a NextToken is found, do pagination until last page
+	var attachments []*ec2.TransitGatewayAttachment
+	for {
+		exporterMetrics.IncrementRequests(ec2.ServiceName, "DescribeTransitGatewayAttachments")
+		result, err := svc.DescribeTransitGatewayAttachments(input)
+		if err != nil {
+			level.Error(e.logger).Log("msg", "Call to DescribeTransitGatewayAttachments failed", "region", region, "err", err)
+			exporterMetrics.IncrementErrors(ec2.ServiceName, "DescribeTransitGatewayAttachments", err)
+			return
+		}
+		attachments = append(attachments, result.TransitGatewayAttachments...)
+		input.NextToken = result.NextToken
+		if result.NextToken == nil {
+			break
+		}
+	}
+
+	for _, attachment := range attachments {
+		ch <- prometheus.MustNewConstMetric(e.AttachmentState, prometheus.GaugeValue, 1, region,
+			aws.StringValue(attachment.TransitGatewayId), *attachment.TransitGatewayAttachmentId, aws.StringValue(attachment.ResourceType), aws.StringValue(attachment.State))
+	}
+}